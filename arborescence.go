@@ -0,0 +1,180 @@
+package graph
+
+import (
+	"errors"
+)
+
+// An arc of the working graph used by the Chu-Liu/Edmonds algorithm.
+type branchArc struct {
+	from, to int
+	weight   float64
+	id       int // position of the original arc
+}
+
+// Find the minimum-cost arborescence rooted at the vertex 'rootKey' with the Chu-Liu/Edmonds algorithm.
+// The chosen arcs and their vertices are marked with InTree. The result lists the chosen arcs and the total weight.
+func (graph *Graph) FindMinArborescence(rootKey string) ([]Edge, float64, error) {
+	if graph.First == nil {
+		return nil, 0, errors.New("Graph is empty")
+	}
+	root := graph.findVertex(rootKey)
+	if root == nil {
+		return nil, 0, errors.New("Root key not found")
+	}
+	vertices, index := graph.indexVertices()
+
+	// every vertex must be reachable from the root
+	vPtr := graph.First
+	for vPtr != nil {
+		vPtr.Processed = false
+		vPtr = vPtr.NextVertex
+	}
+	queue := NewQueue(false)
+	queue.Enqueue(root)
+	root.Processed = true
+	for !queue.IsEmpty() {
+		queue.Dequeue(&vPtr)
+		for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			if !aPtr.Dest.Processed {
+				aPtr.Dest.Processed = true
+				queue.Enqueue(aPtr.Dest)
+			}
+		}
+	}
+	for _, v := range vertices {
+		if !v.Processed {
+			return nil, 0, errors.New("Vertex " + v.Key + " is not reachable from the root")
+		}
+	}
+
+	var arcs []*Arc
+	var sources []*Vertex
+	var work []branchArc
+	for i, v := range vertices {
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			if aPtr.Dest == v || aPtr.Dest == root {
+				continue // self loops and arcs into the root are never used
+			}
+			work = append(work, branchArc{from: i, to: index[aPtr.Dest], weight: aPtr.Weight, id: len(arcs)})
+			arcs = append(arcs, aPtr)
+			sources = append(sources, v)
+		}
+	}
+
+	chosen := minBranching(len(vertices), index[root], work)
+
+	for _, v := range vertices {
+		v.InTree = false
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			aPtr.InTree = false
+		}
+	}
+	root.InTree = true
+	result := make([]Edge, 0, len(chosen))
+	total := 0.0
+	for _, id := range chosen {
+		arc := arcs[id]
+		arc.InTree = true
+		arc.Dest.InTree = true
+		result = append(result, Edge{FromKey: sources[id].Key, ToKey: arc.Dest.Key, Weight: arc.Weight})
+		total += arc.Weight
+	}
+	// report the arcs in the order of their destination vertices
+	for i := 1; i < len(result); i++ {
+		for j := i; j > 0 && result[j].ToKey < result[j-1].ToKey; j-- {
+			result[j], result[j-1] = result[j-1], result[j]
+		}
+	}
+	return result, total, nil
+}
+
+// Return the ids of the arcs in a minimum branching of 'n' vertices rooted at 'root'.
+// Every vertex is assumed to be reachable from the root. Cycles of cheapest incoming arcs
+// are contracted into a single vertex and the problem is solved recursively.
+func minBranching(n, root int, work []branchArc) []int {
+	// cheapest incoming arc of every vertex
+	in := make([]int, n)
+	for v := range in {
+		in[v] = -1
+	}
+	for i, a := range work {
+		if a.from == a.to || a.to == root {
+			continue
+		}
+		if in[a.to] == -1 || a.weight < work[in[a.to]].weight {
+			in[a.to] = i
+		}
+	}
+
+	// look for a cycle among the cheapest incoming arcs
+	comp := make([]int, n)
+	mark := make([]int, n)
+	for v := range comp {
+		comp[v] = -1
+		mark[v] = -1
+	}
+	count := 0
+	cycles := 0
+	for v := 0; v < n; v++ {
+		u := v
+		for u != root && mark[u] == -1 && in[u] != -1 {
+			mark[u] = v
+			u = work[in[u]].from
+		}
+		if u != root && mark[u] == v && in[u] != -1 && comp[u] == -1 { // found a new cycle through u
+			for w := work[in[u]].from; w != u; w = work[in[w]].from {
+				comp[w] = count
+			}
+			comp[u] = count
+			count++
+			cycles++
+		}
+	}
+	if cycles == 0 {
+		var result []int
+		for v := 0; v < n; v++ {
+			if v != root && in[v] != -1 {
+				result = append(result, work[in[v]].id)
+			}
+		}
+		return result
+	}
+
+	// contract every cycle into one vertex
+	for v := 0; v < n; v++ {
+		if comp[v] == -1 {
+			comp[v] = count
+			count++
+		}
+	}
+	var contracted []branchArc
+	var origin []int // position in 'work' of every contracted arc
+	for i, a := range work {
+		if comp[a.from] == comp[a.to] {
+			continue
+		}
+		weight := a.weight
+		if in[a.to] != -1 && comp[a.to] < cycles {
+			weight -= work[in[a.to]].weight
+		}
+		contracted = append(contracted, branchArc{from: comp[a.from], to: comp[a.to], weight: weight, id: len(origin)})
+		origin = append(origin, i)
+	}
+	sub := minBranching(count, comp[root], contracted)
+
+	var result []int
+	entered := make(map[int]bool) // vertices of a cycle entered from outside
+	for _, id := range sub {
+		a := work[origin[id]]
+		result = append(result, a.id)
+		if comp[a.to] < cycles {
+			entered[a.to] = true
+		}
+	}
+	for v := 0; v < n; v++ {
+		if comp[v] < cycles && !entered[v] {
+			result = append(result, work[in[v]].id)
+		}
+	}
+	return result
+}
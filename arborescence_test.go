@@ -0,0 +1,51 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"testing"
+)
+
+func TestMinArborescence(t *testing.T) {
+	fmt.Println("Testing minimum-cost arborescence")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	result, total, err := g.FindMinArborescence("a")
+	fmt.Println(result, total)
+	if err != nil {
+		t.Errorf("Error should be NIL")
+	}
+	expected := []graph.Edge{
+		{FromKey: "a", ToKey: "b", Weight: 5},
+		{FromKey: "b", ToKey: "c", Weight: 4},
+		{FromKey: "a", ToKey: "d", Weight: 5},
+		{FromKey: "c", ToKey: "e", Weight: 2}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Solution is not correct")
+	}
+	if total != 16 {
+		t.Errorf("The total weight should be 16")
+	}
+	for v := g.First; v != nil; v = v.NextVertex {
+		if !v.InTree {
+			t.Errorf("Vertex %s should be in the tree", v.Key)
+		}
+	}
+}
+
+func TestMinArborescenceUnreachable(t *testing.T) {
+	fmt.Println("Testing minimum-cost arborescence with an unreachable vertex")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	_, _, err := g.FindMinArborescence("c")
+	if err == nil {
+		t.Errorf("Error should NOT be NIL")
+	}
+	_, _, err = g.FindMinArborescence("x")
+	if err == nil {
+		t.Errorf("Error should NOT be NIL")
+	}
+}
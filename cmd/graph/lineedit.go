@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// lineEditor reads lines from a terminal in raw mode. It supports moving and deleting in the line, recalling the
+// history with the up and down arrows and completing the word before the cursor with the tab key.
+type lineEditor struct {
+	in      *bufio.Reader
+	out     io.Writer
+	history []string
+
+	// complete returns the position in 'line' where the word before 'pos' starts and the texts that can replace it.
+	complete func(line []rune, pos int) (int, []string)
+}
+
+// Keys read in raw mode.
+const (
+	keyCtrlA     = 1
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyBackspace = 8
+	keyTab       = 9
+	keyCtrlK     = 11
+	keyEnter     = 13
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// Add a line to the history, unless it is empty or repeats the previous line.
+func (e *lineEditor) addHistory(line string) {
+	if strings.TrimSpace(line) != "" && (len(e.history) == 0 || e.history[len(e.history)-1] != line) {
+		e.history = append(e.history, line)
+	}
+}
+
+// Return the longest common prefix of 'texts'.
+func commonPrefix(texts []string) string {
+	prefix := []rune(texts[0])
+	for _, text := range texts[1:] {
+		runes := []rune(text)
+		n := 0
+		for n < len(prefix) && n < len(runes) && prefix[n] == runes[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
+
+// Read a line after showing 'prompt'. Ctrl-C abandons the line and returns an empty one; Ctrl-D on an empty
+// line returns io.EOF.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	var line []rune
+	pos := 0
+	recalled := len(e.history) // position in the history of the line shown, len(e.history) for a new line
+	var draft []rune           // the new line while the history is shown
+	refresh := func() {
+		fmt.Fprintf(e.out, "\r%s%s\x1b[K", prompt, string(line))
+		if pos < len(line) {
+			fmt.Fprintf(e.out, "\x1b[%dD", len(line)-pos)
+		}
+	}
+	recall := func(i int) {
+		if recalled == len(e.history) {
+			draft = line
+		}
+		recalled = i
+		if i == len(e.history) {
+			line = draft
+		} else {
+			line = []rune(e.history[i])
+		}
+		pos = len(line)
+	}
+	refresh()
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return string(line), nil
+			}
+			return "", err
+		}
+		switch r {
+		case keyEnter, '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(line), nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", nil
+		case keyCtrlD:
+			if len(line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if pos < len(line) {
+				line = append(line[:pos:pos], line[pos+1:]...)
+			}
+		case keyBackspace, keyDelete:
+			if pos > 0 {
+				line = append(line[:pos-1:pos-1], line[pos:]...)
+				pos--
+			}
+		case keyCtrlA:
+			pos = 0
+		case keyCtrlE:
+			pos = len(line)
+		case keyCtrlK:
+			line = line[:pos]
+		case keyCtrlU:
+			line, pos = line[pos:], 0
+		case keyTab:
+			if e.complete == nil {
+				break
+			}
+			start, candidates := e.complete(line, pos)
+			word := string(line[start:pos])
+			insert := ""
+			switch {
+			case len(candidates) == 0:
+				fmt.Fprint(e.out, "\a")
+			case len(candidates) == 1:
+				insert = candidates[0] + " "
+			case len(commonPrefix(candidates)) > len(word):
+				insert = commonPrefix(candidates)
+			default:
+				fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+			}
+			if insert != "" {
+				rest := line[pos:]
+				line = append(append(line[:start:start], []rune(insert)...), rest...)
+				pos = start + len([]rune(insert))
+			}
+		case keyEscape:
+			// arrows and other keys send ESC [ or ESC O followed by a letter or a tilde, maybe after digits and semicolons
+			next, _, _ := e.in.ReadRune()
+			if next != '[' && next != 'O' {
+				break
+			}
+			final, _, _ := e.in.ReadRune()
+			for final >= '0' && final <= '9' || final == ';' {
+				digit := final
+				if final, _, _ = e.in.ReadRune(); final == '~' && digit == '3' && pos < len(line) {
+					line = append(line[:pos:pos], line[pos+1:]...)
+				}
+			}
+			switch final {
+			case 'A':
+				if recalled > 0 {
+					recall(recalled - 1)
+				}
+			case 'B':
+				if recalled < len(e.history) {
+					recall(recalled + 1)
+				}
+			case 'C':
+				pos = min(pos+1, len(line))
+			case 'D':
+				pos = max(pos-1, 0)
+			case 'H':
+				pos = 0
+			case 'F':
+				pos = len(line)
+			}
+		default:
+			if r >= ' ' {
+				line = append(line[:pos:pos], append([]rune{r}, line[pos:]...)...)
+				pos++
+			}
+		}
+		refresh()
+	}
+}
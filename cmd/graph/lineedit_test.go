@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Read lines from the keys 'keys' with an editor completing the words "apple", "apricot" and "banana".
+func readLines(keys string, history ...string) ([]string, string, error) {
+	var out strings.Builder
+	editor := &lineEditor{in: bufio.NewReader(strings.NewReader(keys)), out: &out, history: history}
+	editor.complete = func(line []rune, pos int) (int, []string) {
+		start := strings.LastIndex(string(line[:pos]), " ") + 1
+		var candidates []string
+		for _, choice := range []string{"apple", "apricot", "banana"} {
+			if strings.HasPrefix(choice, string(line[start:pos])) {
+				candidates = append(candidates, choice)
+			}
+		}
+		return start, candidates
+	}
+	var lines []string
+	for {
+		line, err := editor.readLine("> ")
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return lines, out.String(), err
+		}
+		lines = append(lines, line)
+		editor.addHistory(line)
+	}
+}
+
+func TestLineEditor(t *testing.T) {
+	fmt.Println("Testing line editing")
+	for _, test := range []struct {
+		keys    string
+		history []string
+		lines   []string
+	}{
+		{"abc\r", nil, []string{"abc"}},
+		{"abd\x7fc\rxy", nil, []string{"abc", "xy"}},
+		{"bc\x1b[D\x1b[Da\x1b[C\x1b[3~\r", nil, []string{"ab"}},
+		{"bc\x01a\x05d\r", nil, []string{"abcd"}},
+		{"abc\x1b[D\x0b\r", nil, []string{"ab"}},
+		{"abc\x1b[D\x15\r", nil, []string{"c"}},
+		{"ab\x03cd\r", nil, []string{"", "cd"}},
+		{"ab\x1b[1;5Dc\r", nil, []string{"acb"}},
+		{"x\x1b[A\r", []string{"one", "two"}, []string{"two"}},
+		{"x\x1b[A\x1b[A\x1b[A\x1b[B\r", []string{"one", "two"}, []string{"two"}},
+		{"x\x1b[A\x1b[By\r", []string{"one"}, []string{"xy"}},
+		{"one\r\x1b[A\x1b[A\r", []string{"zero"}, []string{"one", "zero"}},
+		{"eat b\t\r", nil, []string{"eat banana "}},
+		{"a\tr\t\r", nil, []string{"apricot "}},
+		{"ap\t\r", nil, []string{"ap"}},
+		{"c\t\r", nil, []string{"c"}},
+		{"ab\x04\r\x04", nil, []string{"ab"}},
+	} {
+		lines, _, err := readLines(test.keys, test.history...)
+		if err != nil || strings.Join(lines, "|") != strings.Join(test.lines, "|") {
+			t.Errorf("%q: expected %q, got %q (%v)", test.keys, test.lines, lines, err)
+		}
+	}
+
+	// several candidates sharing no longer prefix are listed below the line
+	_, out, _ := readLines("ap\t\r")
+	if !strings.Contains(out, "\r\napple  apricot\r\n") {
+		t.Errorf("The candidates should be listed: %q", out)
+	}
+	_, out, _ = readLines("c\t\r")
+	if !strings.Contains(out, "\a") {
+		t.Errorf("A word without candidates should ring the bell: %q", out)
+	}
+	// the cursor is moved back to its place after the line is drawn
+	_, out, _ = readLines("abc\x1b[D\x1b[D\r")
+	if !strings.Contains(out, "\r> abc\x1b[K\x1b[2D\r\n") {
+		t.Errorf("The cursor should be 2 characters back: %q", out)
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	fmt.Println("Testing common prefix of candidates")
+	for _, test := range []struct {
+		texts  []string
+		prefix string
+	}{
+		{[]string{"apple", "apricot"}, "ap"},
+		{[]string{"apple"}, "apple"},
+		{[]string{"été", "étage"}, "ét"},
+		{[]string{"a", "b"}, ""},
+	} {
+		if prefix := commonPrefix(test.texts); prefix != test.prefix {
+			t.Errorf("%q: expected %q, got %q", test.texts, test.prefix, prefix)
+		}
+	}
+}
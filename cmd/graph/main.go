@@ -0,0 +1,391 @@
+// Command graph answers route queries on a graph read from a file.
+//
+// Usage:
+//
+//	graph -graph FILE [-format FORMAT] [-json] COMMAND [ARGUMENTS]
+//
+// The graph is read as a route spec, a CSV edge list, a JSON document or a DOT digraph, chosen by -format
+// (spec, csv, json or dot) or else by the extension of FILE (.csv, .json, .dot or .gv; anything else is a route
+// spec). The flags can also follow the command. The commands are:
+//
+//	distance A-B-C                                   distance along a route; the keys may also be given separately
+//	shortest A C                                     shortest route from A to C and its distance
+//	trips -from C -to C [-min-stops 1] -max-stops 3  every trip from C to C with 1 to 3 stops
+//	trips -from A -to C -stops 4                     every trip from A to C with exactly 4 stops
+//	roundtrips -from C -max-weight 30                every round trip from C lighter than 30
+//	repl                                             an interactive session to edit and query the graph
+//
+// The repl command reads commands to add, remove and reweight vertices and arcs, undo and redo changes, list
+// vertices, arcs, neighbours and degrees, run the queries above and the other Find* methods of the library, and
+// load or save the graph. Without -graph it starts from an empty graph. On a terminal, the tab key completes
+// commands and vertex keys and the arrows recall the history; type help for the commands.
+//
+// With -json, the results and errors of the queries, but not of repl, are written to the standard output as JSON
+// objects. The exit status is 0 on success, 1 when there is no such route or trip, 2 on a usage error, 3 when the
+// graph cannot be read and 4 when a vertex key is not in the graph.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/audathuynh/graph"
+)
+
+// Usage of the interactive session, which starts from an empty graph without -graph.
+const replUsage = "repl"
+
+// Exit statuses.
+const (
+	exitOK         = 0
+	exitNoResult   = 1
+	exitUsage      = 2
+	exitInput      = 3
+	exitUnknownKey = 4
+)
+
+// commandError is an error with the exit status it causes.
+type commandError struct {
+	status  int
+	message string
+}
+
+func (err *commandError) Error() string {
+	return err.message
+}
+
+// Options shared by the commands.
+type options struct {
+	graphFile string
+	format    string
+	json      bool
+}
+
+// Add the shared flags to a flag set.
+func (o *options) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.graphFile, "graph", o.graphFile, "file to read the graph from")
+	flags.StringVar(&o.format, "format", o.format, "format of the graph file: spec, csv, json or dot")
+	flags.BoolVar(&o.json, "json", o.json, "write results as JSON")
+}
+
+// Parse 'args', allowing flags after the positional arguments, and return the positional arguments.
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// Return the format of a graph file, from 'format' or else from the extension of 'path'.
+func graphFormat(path, format string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			return "csv", nil
+		case ".json":
+			return "json", nil
+		case ".dot", ".gv":
+			return "dot", nil
+		}
+		return "spec", nil
+	}
+	switch format {
+	case "spec", "csv", "json", "dot":
+		return format, nil
+	}
+	return "", errors.New("unknown format " + strconv.Quote(format))
+}
+
+// Read a graph from the file 'path' in the format 'format', or the format told by its extension when empty.
+func loadGraph(path, format string) (*graph.Graph, error) {
+	format, err := graphFormat(path, format)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var g *graph.Graph
+	switch format {
+	case "csv":
+		g, err = graph.ReadCSVEdges(file, graph.CSVOptions{})
+	case "json":
+		g, err = graph.ReadJSON(file)
+	case "dot":
+		g, err = graph.ReadDOT(file)
+	default:
+		var data []byte
+		if data, err = io.ReadAll(file); err == nil {
+			g, err = graph.ParseRouteSpec(string(data))
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return g, nil
+}
+
+// Check that the vertices 'keys' are in the graph.
+func checkKeys(g *graph.Graph, keys ...string) error {
+	for _, key := range keys {
+		vPtr := g.First
+		for vPtr != nil && vPtr.Key != key {
+			vPtr = vPtr.NextVertex
+		}
+		if vPtr == nil {
+			return &commandError{status: exitUnknownKey, message: "unknown vertex " + strconv.Quote(key)}
+		}
+	}
+	return nil
+}
+
+func formatNumber(x float64) string {
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
+
+// Results of the commands, as written with -json.
+type routeResult struct {
+	Route    []string `json:"route"`
+	Distance float64  `json:"distance"`
+}
+
+type tripsResult struct {
+	Trips [][]string `json:"trips"`
+	Count int        `json:"count"`
+}
+
+type errorResult struct {
+	Error  string `json:"error"`
+	Status int    `json:"status"`
+}
+
+// A command adds its flags to a flag set with setup, which returns the query run with the positional arguments.
+type command struct {
+	usage string
+	setup func(flags *flag.FlagSet) func(g *graph.Graph, args []string) (interface{}, error)
+}
+
+var commands = map[string]command{
+	"distance": {
+		usage: "distance A-B-C",
+		setup: func(flags *flag.FlagSet) func(g *graph.Graph, args []string) (interface{}, error) {
+			return func(g *graph.Graph, args []string) (interface{}, error) {
+				route := args
+				if len(args) == 1 {
+					route = strings.Split(args[0], "-")
+				}
+				if len(route) < 2 {
+					return nil, &commandError{status: exitUsage, message: "a route needs at least two vertices"}
+				}
+				if err := checkKeys(g, route...); err != nil {
+					return nil, err
+				}
+				distance, err := g.FindDistance(route)
+				if err != nil {
+					return nil, &commandError{status: exitNoResult, message: "no such route"}
+				}
+				return routeResult{Route: route, Distance: distance}, nil
+			}
+		},
+	},
+	"shortest": {
+		usage: "shortest A C",
+		setup: func(flags *flag.FlagSet) func(g *graph.Graph, args []string) (interface{}, error) {
+			return func(g *graph.Graph, args []string) (interface{}, error) {
+				if len(args) != 2 {
+					return nil, &commandError{status: exitUsage, message: "shortest needs an origin and a destination"}
+				}
+				if err := checkKeys(g, args...); err != nil {
+					return nil, err
+				}
+				route, distance, err := g.FindBestRoute(args[0], args[1], graph.ShortestPath{})
+				if err != nil {
+					return nil, &commandError{status: exitNoResult, message: "no route from " + args[0] + " to " + args[1]}
+				}
+				return routeResult{Route: route, Distance: distance}, nil
+			}
+		},
+	},
+	"trips": {
+		usage: "trips -from A -to C [-min-stops N] -max-stops N | -stops N",
+		setup: func(flags *flag.FlagSet) func(g *graph.Graph, args []string) (interface{}, error) {
+			from := flags.String("from", "", "origin of the trips")
+			to := flags.String("to", "", "destination of the trips")
+			minStops := flags.Int("min-stops", 1, "min number of stops")
+			maxStops := flags.Int("max-stops", 0, "max number of stops")
+			stops := flags.Int("stops", 0, "exact number of stops")
+			return func(g *graph.Graph, args []string) (interface{}, error) {
+				if *stops > 0 {
+					*minStops, *maxStops = *stops, *stops
+				}
+				if len(args) != 0 || *from == "" || *to == "" || *maxStops < 1 || *minStops > *maxStops {
+					return nil, &commandError{status: exitUsage, message: "trips needs -from, -to and -max-stops or -stops"}
+				}
+				if err := checkKeys(g, *from, *to); err != nil {
+					return nil, err
+				}
+				trips, err := g.FindTrips(*from, *to, *minStops, *maxStops)
+				if err != nil {
+					return nil, err
+				}
+				return tripsResult{Trips: trips, Count: len(trips)}, nil
+			}
+		},
+	},
+	"roundtrips": {
+		usage: "roundtrips -from C -max-weight W",
+		setup: func(flags *flag.FlagSet) func(g *graph.Graph, args []string) (interface{}, error) {
+			from := flags.String("from", "", "origin and destination of the round trips")
+			maxWeight := flags.Float64("max-weight", math.NaN(), "round trips must weigh less than this")
+			return func(g *graph.Graph, args []string) (interface{}, error) {
+				if len(args) != 0 || *from == "" || math.IsNaN(*maxWeight) {
+					return nil, &commandError{status: exitUsage, message: "roundtrips needs -from and -max-weight"}
+				}
+				if err := checkKeys(g, *from); err != nil {
+					return nil, err
+				}
+				trips, err := g.FindRoundTripWithMaxWeight(*from, *maxWeight)
+				if err != nil {
+					return nil, err
+				}
+				return tripsResult{Trips: trips, Count: len(trips)}, nil
+			}
+		},
+	},
+}
+
+// Write a result for people.
+func writeText(w io.Writer, result interface{}) {
+	switch result := result.(type) {
+	case routeResult:
+		fmt.Fprintf(w, "%s\ndistance %s\n", strings.Join(result.Route, "-"), formatNumber(result.Distance))
+	case tripsResult:
+		for _, trip := range result.Trips {
+			fmt.Fprintln(w, strings.Join(trip, "-"))
+		}
+		fmt.Fprintf(w, "%d trips\n", result.Count)
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, "usage: graph -graph FILE [-format spec|csv|json|dot] [-json] COMMAND [ARGUMENTS]\n\ncommands:\n")
+	for _, name := range []string{"distance", "shortest", "trips", "roundtrips"} {
+		fmt.Fprintf(w, "  %s\n", commands[name].usage)
+	}
+	fmt.Fprintf(w, "  %s\n", replUsage)
+}
+
+// Run the command line 'args', without the program name, and return the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var o options
+	global := flag.NewFlagSet("graph", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.Usage = func() { usage(stderr) }
+	o.register(global)
+	if err := global.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if global.NArg() == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	name := global.Arg(0)
+	cmd, ok := commands[name]
+	if name == "repl" {
+		cmd, ok = command{usage: replUsage}, true
+	}
+	if !ok {
+		fmt.Fprintf(stderr, "graph: unknown command %q\n", name)
+		usage(stderr)
+		return exitUsage
+	}
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	o.register(flags)
+	var query func(g *graph.Graph, args []string) (interface{}, error)
+	if cmd.setup != nil {
+		query = cmd.setup(flags)
+	}
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: graph %s\n", cmd.usage)
+		flags.PrintDefaults()
+	}
+	positional, err := parseFlags(flags, global.Args()[1:])
+	if err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	fail := func(err error) int {
+		status := exitNoResult
+		var cmdErr *commandError
+		if errors.As(err, &cmdErr) {
+			status = cmdErr.status
+		}
+		if o.json {
+			json.NewEncoder(stdout).Encode(errorResult{Error: err.Error(), Status: status})
+		}
+		fmt.Fprintln(stderr, "graph: "+err.Error())
+		return status
+	}
+	if o.graphFile == "" && query != nil {
+		return fail(&commandError{status: exitUsage, message: "no graph file given with -graph"})
+	}
+	if _, err := graphFormat(o.graphFile, o.format); err != nil {
+		return fail(&commandError{status: exitUsage, message: err.Error()})
+	}
+	g := graph.NewGraph()
+	if o.graphFile != "" {
+		if g, err = loadGraph(o.graphFile, o.format); err != nil {
+			return fail(&commandError{status: exitInput, message: err.Error()})
+		}
+	}
+	if query == nil {
+		if len(positional) != 0 {
+			return fail(&commandError{status: exitUsage, message: "usage: graph " + replUsage})
+		}
+		return runREPL(g, o.graphFile, o.format, stdin, stdout, stderr)
+	}
+	result, err := query(g, positional)
+	if err != nil {
+		return fail(err)
+	}
+	status := exitOK
+	if trips, ok := result.(tripsResult); ok && trips.Count == 0 {
+		trips.Trips = [][]string{} // written as [] rather than null
+		result, status = trips, exitNoResult
+	}
+	if o.json {
+		json.NewEncoder(stdout).Encode(result)
+	} else {
+		writeText(stdout, result)
+	}
+	return status
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The graph of the library tests as a route spec.
+const testSpec = "ab5, bc4, cd8, dc8, de6, ad5, ce2, eb3, ae7\n"
+
+// Write 'content' to the file 'name' in a temporary directory and return its path.
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Run a command line and return its exit status and outputs.
+func runCommand(args ...string) (int, string, string) {
+	var stdout, stderr strings.Builder
+	status := run(args, strings.NewReader(""), &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func TestCommands(t *testing.T) {
+	fmt.Println("Testing command line queries")
+	spec := writeFile(t, "routes.txt", testSpec)
+	for _, test := range []struct {
+		args   []string
+		status int
+		output string
+	}{
+		{[]string{"-graph", spec, "distance", "a-b-c"}, exitOK, "a-b-c\ndistance 9\n"},
+		{[]string{"-graph", spec, "distance", "a", "e", "d"}, exitNoResult, ""},
+		{[]string{"-graph", spec, "shortest", "a", "c"}, exitOK, "a-b-c\ndistance 9\n"},
+		{[]string{"shortest", "b", "b", "--graph", spec}, exitOK, "b\ndistance 0\n"},
+		{[]string{"-graph", spec, "trips", "--from", "c", "--to", "c", "--max-stops", "3"}, exitOK, "c-d-c\nc-e-b-c\n2 trips\n"},
+		{[]string{"-graph", spec, "trips", "-from", "a", "-to", "c", "-stops", "4"}, exitOK, "a-b-c-d-c\na-d-c-d-c\na-d-e-b-c\n3 trips\n"},
+		{[]string{"-graph", spec, "trips", "-from", "a", "-to", "a", "-max-stops", "5"}, exitNoResult, "0 trips\n"},
+		{[]string{"-graph", spec, "roundtrips", "-from", "c", "-max-weight", "10"}, exitOK, "c-e-b-c\n1 trips\n"},
+		{[]string{"-graph", spec, "shortest", "a", "x"}, exitUnknownKey, ""},
+		{[]string{"-graph", spec, "trips", "-from", "a"}, exitUsage, ""},
+		{[]string{"-graph", spec, "fly", "a"}, exitUsage, ""},
+		{[]string{"shortest", "a", "c"}, exitUsage, ""},
+		{[]string{"-graph", spec + ".missing", "shortest", "a", "c"}, exitInput, ""},
+		{[]string{"-graph", spec, "-format", "xml", "shortest", "a", "c"}, exitUsage, ""},
+	} {
+		status, stdout, stderr := runCommand(test.args...)
+		if status != test.status || stdout != test.output {
+			t.Errorf("%v: expected status %d and %q, got %d and %q (%s)", test.args, test.status, test.output, status, stdout, stderr)
+		}
+		if status != exitOK && status != exitNoResult && stderr == "" {
+			t.Errorf("%v: the error should be reported", test.args)
+		}
+	}
+}
+
+func TestCommandsJSON(t *testing.T) {
+	fmt.Println("Testing command line JSON output")
+	spec := writeFile(t, "routes.txt", testSpec)
+	status, stdout, _ := runCommand("-graph", spec, "-json", "shortest", "a", "c")
+	var route routeResult
+	if err := json.Unmarshal([]byte(stdout), &route); err != nil || status != exitOK {
+		t.Errorf("Expected a route, got %d and %q", status, stdout)
+	}
+	if !reflect.DeepEqual(route, routeResult{Route: []string{"a", "b", "c"}, Distance: 9}) {
+		t.Errorf("Wrong route %v", route)
+	}
+	status, stdout, _ = runCommand("-graph", spec, "trips", "-from", "a", "-to", "a", "-max-stops", "2", "-json")
+	if status != exitNoResult || stdout != "{\"trips\":[],\"count\":0}\n" {
+		t.Errorf("Expected no trips, got %d and %q", status, stdout)
+	}
+	status, stdout, _ = runCommand("-graph", spec, "-json", "distance", "a-x")
+	if status != exitUnknownKey || stdout != "{\"error\":\"unknown vertex \\\"x\\\"\",\"status\":4}\n" {
+		t.Errorf("Expected an error, got %d and %q", status, stdout)
+	}
+}
+
+func TestFormats(t *testing.T) {
+	fmt.Println("Testing command line graph formats")
+	files := []string{
+		writeFile(t, "routes.csv", "from,to,weight\na,b,5\nb,c,4\n"),
+		writeFile(t, "routes.json", `{"version":1,"vertices":[{"key":"a"},{"key":"b"},{"key":"c"}],"arcs":[{"from":"a","to":"b","weight":5},{"from":"b","to":"c","weight":4}]}`),
+		writeFile(t, "routes.gv", "digraph { a -> b [weight=5]; b -> c [weight=4] }"),
+	}
+	for _, file := range files {
+		status, stdout, stderr := runCommand("-graph", file, "shortest", "a", "c")
+		if status != exitOK || stdout != "a-b-c\ndistance 9\n" {
+			t.Errorf("%s: expected the route a-b-c, got %d and %q (%s)", file, status, stdout, stderr)
+		}
+	}
+	// the format flag overrides the extension
+	status, _, stderr := runCommand("-graph", files[0], "-format", "spec", "shortest", "a", "c")
+	if status != exitInput {
+		t.Errorf("A CSV file should not be read as a route spec, got %d (%s)", status, stderr)
+	}
+}
@@ -0,0 +1,1035 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/audathuynh/graph"
+)
+
+// Number of changes that can be undone.
+const maxUndo = 100
+
+// repl is an interactive session on a graph. Every change is recorded as the operations made on the graph, which
+// are undone in reverse order.
+type repl struct {
+	g       *graph.Graph
+	file    string // file last loaded or saved, used by save without arguments
+	format  string
+	undo    [][]operation
+	redo    [][]operation
+	changes []operation // operations of the command being run
+	editor  *lineEditor
+	out     io.Writer
+	errOut  io.Writer
+}
+
+// operation is a change of the graph that can be undone. Redoing it runs do again on the graph left by undo.
+type operation struct {
+	do   func() error
+	undo func()
+}
+
+// replCommand is a command of the REPL besides the queries of the command line.
+type replCommand struct {
+	usage string
+	help  string
+	edit  bool // the command changes the graph and can be undone
+	run   func(r *repl, args []string) error
+}
+
+// A word of a command line and the position of its first character in the line.
+type word struct {
+	start int
+	text  string
+}
+
+// Split a line into words separated by spaces. A word in double quotes may contain spaces, and a backslash in it
+// escapes the next character. The result tells whether the line ends inside quotes.
+func scanWords(line []rune) ([]word, bool) {
+	var words []word
+	i := 0
+	for {
+		for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		if i == len(line) {
+			return words, false
+		}
+		w := word{start: i}
+		if line[i] == '"' {
+			var text []rune
+			for i++; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' && i+1 < len(line) {
+					i++
+				}
+				text = append(text, line[i])
+			}
+			w.text = string(text)
+			words = append(words, w)
+			if i == len(line) {
+				return words, true
+			}
+			i++
+		} else {
+			for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+				i++
+			}
+			w.text = string(line[w.start:i])
+			words = append(words, w)
+		}
+	}
+}
+
+// Return the words of a command line.
+func splitLine(line string) ([]string, error) {
+	words, open := scanWords([]rune(line))
+	if open {
+		return nil, errors.New("missing closing quote")
+	}
+	texts := make([]string, len(words))
+	for i, w := range words {
+		texts[i] = w.text
+	}
+	return texts, nil
+}
+
+// Quote a vertex key when it cannot be typed as a single word.
+func quoteKey(key string) string {
+	if key != "" && !strings.ContainsAny(key, " \t\"\\") {
+		return key
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(key) + `"`
+}
+
+// Return the position of the word before 'pos' and the texts that can replace it: command names for the first
+// word and vertex keys for the arguments.
+func (r *repl) complete(line []rune, pos int) (int, []string) {
+	words, open := scanWords(line[:pos])
+	current := word{start: pos}
+	if len(words) > 0 && (open || pos > 0 && line[pos-1] != ' ' && line[pos-1] != '\t') {
+		current = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	var choices []string
+	switch {
+	case len(words) == 0:
+		choices = append([]string{"help", "quit", "exit"}, replCommandNames()...)
+		for name := range commands {
+			choices = append(choices, name)
+		}
+	case (words[0].text == "add" || words[0].text == "remove") && len(words) == 1:
+		choices = []string{"vertex", "arc"}
+	case words[0].text == "allpairs" && len(words) == 1:
+		for name := range semirings {
+			choices = append(choices, name)
+		}
+	case words[0].text == "load" || words[0].text == "save" || strings.HasPrefix(current.text, "-"):
+		return current.start, nil
+	default:
+		for vPtr := r.g.First; vPtr != nil; vPtr = vPtr.NextVertex {
+			choices = append(choices, vPtr.Key)
+		}
+	}
+	var candidates []string
+	for _, choice := range choices {
+		if strings.HasPrefix(choice, current.text) {
+			candidates = append(candidates, quoteKey(choice))
+		}
+	}
+	sort.Strings(candidates)
+	return current.start, candidates
+}
+
+// Return the vertex with the key 'key'.
+func (r *repl) vertex(key string) (*graph.Vertex, error) {
+	for vPtr := r.g.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		if vPtr.Key == key {
+			return vPtr, nil
+		}
+	}
+	return nil, errors.New("unknown vertex " + strconv.Quote(key))
+}
+
+func parseNumber(text string) (float64, error) {
+	x, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, errors.New("invalid number " + strconv.Quote(text))
+	}
+	return x, nil
+}
+
+// errUsage is returned by the commands given wrong arguments, and reported with their usage.
+var errUsage = errors.New("wrong arguments")
+
+// Check the number of arguments of a command; 'max' is -1 when there is no limit.
+func needArgs(args []string, min, max int) error {
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		return errUsage
+	}
+	return nil
+}
+
+func (r *repl) printRoute(route []string, label string, value float64) {
+	fmt.Fprintf(r.out, "%s\n%s %s\n", strings.Join(route, "-"), label, formatNumber(value))
+}
+
+func (r *repl) printEdges(edges []graph.Edge, arrow string) {
+	for _, edge := range edges {
+		fmt.Fprintf(r.out, "%s %s %s %s\n", edge.FromKey, arrow, edge.ToKey, formatNumber(edge.Weight))
+	}
+}
+
+// Path algebras of the allpairs command.
+var semirings = map[string]graph.Semiring{
+	"shortest": graph.ShortestPath{},
+	"widest":   graph.WidestPath{},
+	"reliable": graph.ReliablePath{},
+}
+
+// Return the weight of an arc, followed by its capacity when it has one.
+func formatArc(aPtr *graph.Arc) string {
+	if aPtr.HasCapacity {
+		return formatNumber(aPtr.Weight) + "  capacity " + formatNumber(aPtr.Capacity)
+	}
+	return formatNumber(aPtr.Weight)
+}
+
+// Return a query on two vertices printing a route and its value.
+func routeQuery(label string, find func(g *graph.Graph, fromKey, toKey string) ([]string, float64, error)) func(r *repl, args []string) error {
+	return func(r *repl, args []string) error {
+		if err := needArgs(args, 2, 2); err != nil {
+			return err
+		}
+		route, value, err := find(r.g, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		r.printRoute(route, label, value)
+		return nil
+	}
+}
+
+var replCommands = map[string]replCommand{
+	"add": {
+		usage: "add vertex KEY... | add arc FROM TO [WEIGHT [CAPACITY]]",
+		help:  "Add vertices, or an arc of weight 1 unless given. The ends of a new arc are added when missing.",
+		edit:  true,
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 2, -1); err != nil {
+				return err
+			}
+			switch args[0] {
+			case "vertex":
+				for _, key := range args[1:] {
+					if _, err := r.vertex(key); err == nil {
+						return errors.New("vertex " + strconv.Quote(key) + " already exists")
+					}
+					r.insertVertex(key)
+				}
+				return nil
+			case "arc":
+				if err := needArgs(args, 3, 5); err != nil {
+					return err
+				}
+				weight, capacity := 1.0, 0.0
+				var err error
+				if len(args) > 3 {
+					if weight, err = parseNumber(args[3]); err != nil {
+						return err
+					}
+				}
+				if len(args) > 4 {
+					if capacity, err = parseNumber(args[4]); err != nil {
+						return err
+					}
+				}
+				for _, key := range args[1:3] {
+					if _, err := r.vertex(key); err != nil {
+						r.insertVertex(key)
+					}
+				}
+				return r.insertArc(args[1], args[2], graph.Arc{Weight: weight, Capacity: capacity, HasCapacity: len(args) > 4})
+			}
+			return errUsage
+		},
+	},
+	"remove": {
+		usage: "remove vertex KEY... | remove arc FROM TO",
+		help:  "Remove vertices together with their arcs, or the arc from FROM to TO inserted last.",
+		edit:  true,
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 2, -1); err != nil {
+				return err
+			}
+			switch args[0] {
+			case "vertex":
+				for _, key := range args[1:] {
+					vPtr, err := r.vertex(key)
+					if err != nil {
+						return err
+					}
+					for vPtr.Arc != nil {
+						r.removeArc(key, vPtr.Arc.Dest.Key)
+					}
+					for fromPtr := r.g.First; fromPtr != nil && vPtr.InDegree > 0; fromPtr = fromPtr.NextVertex {
+						for r.firstArc(fromPtr.Key, key) != nil {
+							r.removeArc(fromPtr.Key, key)
+						}
+					}
+					r.removeVertex(key)
+				}
+				return nil
+			case "arc":
+				if err := needArgs(args, 3, 3); err != nil {
+					return err
+				}
+				if _, err := r.vertex(args[1]); err != nil {
+					return err
+				}
+				return r.removeArc(args[1], args[2])
+			}
+			return errUsage
+		},
+	},
+	"weight": {
+		usage: "weight FROM TO WEIGHT",
+		help:  "Change the weight of the arc from FROM to TO inserted last.",
+		edit:  true,
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 3, 3); err != nil {
+				return err
+			}
+			weight, err := parseNumber(args[2])
+			if err != nil {
+				return err
+			}
+			if _, err := r.vertex(args[0]); err != nil {
+				return err
+			}
+			return r.setWeight(args[0], args[1], weight)
+		},
+	},
+	"new": {
+		usage: "new",
+		help:  "Start from an empty graph.",
+		edit:  true,
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 0, 0); err != nil {
+				return err
+			}
+			r.replaceGraph(graph.NewGraph())
+			return nil
+		},
+	},
+	"load": {
+		usage: "load FILE [spec|csv|json|dot]",
+		help:  "Replace the graph with the graph read from FILE, in the format told by its extension unless given.",
+		edit:  true,
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 1, 2); err != nil {
+				return err
+			}
+			format := ""
+			if len(args) == 2 {
+				format = args[1]
+			}
+			g, err := loadGraph(args[0], format)
+			if err != nil {
+				return err
+			}
+			r.replaceGraph(g)
+			r.file, r.format = args[0], format
+			fmt.Fprintf(r.out, "%d vertices\n", g.Count)
+			return nil
+		},
+	},
+	"save": {
+		usage: "save [FILE [spec|csv|json|dot]]",
+		help:  "Write the graph to FILE, or to the file last loaded or saved, in the format told by its extension unless given.",
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 0, 2); err != nil {
+				return err
+			}
+			file, format := r.file, r.format
+			if len(args) > 0 {
+				file, format = args[0], ""
+			}
+			if len(args) == 2 {
+				format = args[1]
+			}
+			if file == "" {
+				return errors.New("no file to save to")
+			}
+			if err := saveGraph(r.g, file, format); err != nil {
+				return err
+			}
+			r.file, r.format = file, format
+			return nil
+		},
+	},
+	"undo": {
+		usage: "undo",
+		help:  "Undo the last change of the graph.",
+		run: func(r *repl, args []string) error {
+			if len(r.undo) == 0 {
+				return errors.New("nothing to undo")
+			}
+			changes := r.undo[len(r.undo)-1]
+			undoChanges(changes)
+			r.undo = r.undo[:len(r.undo)-1]
+			r.redo = append(r.redo, changes)
+			return nil
+		},
+	},
+	"redo": {
+		usage: "redo",
+		help:  "Redo the last change undone.",
+		run: func(r *repl, args []string) error {
+			if len(r.redo) == 0 {
+				return errors.New("nothing to redo")
+			}
+			changes := r.redo[len(r.redo)-1]
+			for i, op := range changes {
+				if err := op.do(); err != nil {
+					undoChanges(changes[:i])
+					return err
+				}
+			}
+			r.redo = r.redo[:len(r.redo)-1]
+			r.undo = append(r.undo, changes)
+			return nil
+		},
+	},
+	"vertices": {
+		usage: "vertices",
+		help:  "List the vertices with their degrees.",
+		run: func(r *repl, args []string) error {
+			for vPtr := r.g.First; vPtr != nil; vPtr = vPtr.NextVertex {
+				fmt.Fprintf(r.out, "%s  in %d  out %d\n", vPtr.Key, vPtr.InDegree, vPtr.OutDegree)
+			}
+			fmt.Fprintf(r.out, "%d vertices\n", r.g.Count)
+			return nil
+		},
+	},
+	"arcs": {
+		usage: "arcs",
+		help:  "List the arcs with their weights and capacities.",
+		run: func(r *repl, args []string) error {
+			count := 0
+			for vPtr := r.g.First; vPtr != nil; vPtr = vPtr.NextVertex {
+				for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+					fmt.Fprintf(r.out, "%s -> %s %s\n", vPtr.Key, aPtr.Dest.Key, formatArc(aPtr))
+					count++
+				}
+			}
+			fmt.Fprintf(r.out, "%d arcs\n", count)
+			return nil
+		},
+	},
+	"neighbours": {
+		usage: "neighbours KEY",
+		help:  "List the arcs leaving and entering a vertex.",
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 1, 1); err != nil {
+				return err
+			}
+			vPtr, err := r.vertex(args[0])
+			if err != nil {
+				return err
+			}
+			for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+				fmt.Fprintf(r.out, "-> %s %s\n", aPtr.Dest.Key, formatArc(aPtr))
+			}
+			for fromPtr := r.g.First; fromPtr != nil; fromPtr = fromPtr.NextVertex {
+				for aPtr := fromPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+					if aPtr.Dest == vPtr {
+						fmt.Fprintf(r.out, "<- %s %s\n", fromPtr.Key, formatArc(aPtr))
+					}
+				}
+			}
+			return nil
+		},
+	},
+	"degree": {
+		usage: "degree KEY",
+		help:  "Show the numbers of arcs entering and leaving a vertex.",
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 1, 1); err != nil {
+				return err
+			}
+			vPtr, err := r.vertex(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(r.out, "in %d  out %d\n", vPtr.InDegree, vPtr.OutDegree)
+			return nil
+		},
+	},
+	"history": {
+		usage: "history",
+		help:  "List the lines typed so far.",
+		run: func(r *repl, args []string) error {
+			for i, line := range r.editor.history {
+				fmt.Fprintf(r.out, "%4d  %s\n", i+1, line)
+			}
+			return nil
+		},
+	},
+	"roundtrip": {
+		usage: "roundtrip KEY",
+		help:  "Find the shortest round trip from a vertex.",
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 1, 1); err != nil {
+				return err
+			}
+			route, err := r.g.FindShortestRoundTrip(args[0])
+			if err != nil {
+				return err
+			}
+			distance, _ := r.g.FindDistance(route)
+			r.printRoute(route, "distance", distance)
+			return nil
+		},
+	},
+	"widest": {
+		usage: "widest FROM TO",
+		help:  "Find the route whose lightest arc is as heavy as possible.",
+		run:   routeQuery("width", (*graph.Graph).FindWidestRoute),
+	},
+	"reliable": {
+		usage: "reliable FROM TO",
+		help:  "Find the route whose product of weights, between 0 and 1, is the largest.",
+		run:   routeQuery("reliability", (*graph.Graph).FindMostReliableRoute),
+	},
+	"pareto": {
+		usage: "pareto FROM TO [MAX]",
+		help:  "Find the routes that no other route beats on every weight of the arcs.",
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 2, 3); err != nil {
+				return err
+			}
+			maxRoutes := 0
+			if len(args) == 3 {
+				var err error
+				if maxRoutes, err = strconv.Atoi(args[2]); err != nil {
+					return errors.New("invalid number " + strconv.Quote(args[2]))
+				}
+			}
+			routes, err := r.g.FindParetoRoutes(args[0], args[1], maxRoutes)
+			if err != nil {
+				return err
+			}
+			for _, route := range routes {
+				costs := make([]string, len(route.Costs))
+				for i, cost := range route.Costs {
+					costs[i] = formatNumber(cost)
+				}
+				fmt.Fprintf(r.out, "%s  %s\n", strings.Join(route.Route, "-"), strings.Join(costs, " "))
+			}
+			return nil
+		},
+	},
+	"earliest": {
+		usage: "earliest FROM TO DEPARTURE",
+		help:  "Find the route arriving as early as possible, with the arrival time at every stop.",
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 3, 3); err != nil {
+				return err
+			}
+			departure, err := parseNumber(args[2])
+			if err != nil {
+				return err
+			}
+			stops, err := r.g.FindEarliestArrival(args[0], args[1], departure)
+			if err != nil {
+				return err
+			}
+			for _, stop := range stops {
+				fmt.Fprintf(r.out, "%s %s\n", stop.Key, formatNumber(stop.Arrival))
+			}
+			return nil
+		},
+	},
+	"allpairs": {
+		usage: "allpairs [shortest|widest|reliable]",
+		help:  "Show the distances, widths or reliabilities of the best paths between every two vertices; - means no path.",
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 0, 1); err != nil {
+				return err
+			}
+			name := "shortest"
+			if len(args) == 1 {
+				name = args[0]
+			}
+			semiring, ok := semirings[name]
+			if !ok {
+				return errors.New("unknown path algebra " + strconv.Quote(name))
+			}
+			matrix := r.g.FindAllPairs(semiring)
+			table := tabwriter.NewWriter(r.out, 0, 0, 2, ' ', tabwriter.AlignRight)
+			fmt.Fprintf(table, "\t%s\t\n", strings.Join(matrix.Keys, "\t"))
+			for i, key := range matrix.Keys {
+				fmt.Fprintf(table, "%s\t", key)
+				for _, value := range matrix.Values[i] {
+					if value == semiring.Zero() {
+						fmt.Fprint(table, "-\t")
+					} else {
+						fmt.Fprintf(table, "%s\t", formatNumber(value))
+					}
+				}
+				fmt.Fprint(table, "\n")
+			}
+			return table.Flush()
+		},
+	},
+	"arborescence": {
+		usage: "arborescence ROOT",
+		help:  "Find the cheapest set of arcs reaching every vertex from ROOT.",
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 1, 1); err != nil {
+				return err
+			}
+			edges, weight, err := r.g.FindMinArborescence(args[0])
+			if err != nil {
+				return err
+			}
+			r.printEdges(edges, "->")
+			fmt.Fprintf(r.out, "weight %s\n", formatNumber(weight))
+			return nil
+		},
+	},
+	"maxflow": {
+		usage: "maxflow SOURCE SINK",
+		help:  "Find the largest flow from SOURCE to SINK through the capacities of the arcs, and a minimum cut.",
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 2, 2); err != nil {
+				return err
+			}
+			result, err := r.g.FindMaxFlow(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			for _, flow := range result.Flows {
+				if flow.Flow != 0 {
+					fmt.Fprintf(r.out, "%s -> %s %s/%s\n", flow.FromKey, flow.ToKey, formatNumber(flow.Flow), formatNumber(flow.Capacity))
+				}
+			}
+			for _, edge := range result.CutArcs {
+				fmt.Fprintf(r.out, "cut %s -> %s\n", edge.FromKey, edge.ToKey)
+			}
+			fmt.Fprintf(r.out, "flow %s\n", formatNumber(result.Value))
+			return nil
+		},
+	},
+	"mincostflow": {
+		usage: "mincostflow KEY=SUPPLY...",
+		help:  "Find the cheapest flow from the vertices with a positive supply to the vertices with a negative one.",
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 1, -1); err != nil {
+				return err
+			}
+			supply := make(map[string]float64)
+			for _, arg := range args {
+				key, text, ok := strings.Cut(arg, "=")
+				if !ok {
+					return errUsage
+				}
+				value, err := parseNumber(text)
+				if err != nil {
+					return err
+				}
+				supply[key] = value
+			}
+			result, err := r.g.FindMinCostFlow(supply)
+			if err != nil {
+				return err
+			}
+			for _, flow := range result.Flows {
+				if flow.Flow != 0 {
+					fmt.Fprintf(r.out, "%s -> %s %s/%s\n", flow.FromKey, flow.ToKey, formatNumber(flow.Flow), formatNumber(flow.Capacity))
+				}
+			}
+			fmt.Fprintf(r.out, "cost %s\n", formatNumber(result.Cost))
+			return nil
+		},
+	},
+	"matching": {
+		usage: "matching [LEFT...]",
+		help:  "Find a largest matching of a bipartite graph; LEFT is one side, found automatically when not given.",
+		run: func(r *repl, args []string) error {
+			var left []string
+			if len(args) > 0 {
+				left = args
+			}
+			edges, err := r.g.FindMaxMatching(left)
+			if err != nil {
+				return err
+			}
+			r.printEdges(edges, "-")
+			fmt.Fprintf(r.out, "%d pairs\n", len(edges))
+			return nil
+		},
+	},
+	"perfectmatching": {
+		usage: "perfectmatching [LEFT...]",
+		help:  "Find the lightest perfect matching of a bipartite graph; LEFT is one side, found automatically when not given.",
+		run: func(r *repl, args []string) error {
+			var left []string
+			if len(args) > 0 {
+				left = args
+			}
+			edges, weight, err := r.g.FindMinWeightPerfectMatching(left)
+			if err != nil {
+				return err
+			}
+			r.printEdges(edges, "-")
+			fmt.Fprintf(r.out, "weight %s\n", formatNumber(weight))
+			return nil
+		},
+	},
+	"eulerian": {
+		usage: "eulerian",
+		help:  "Find a walk using every arc exactly once.",
+		run: func(r *repl, args []string) error {
+			walk, err := r.g.FindEulerianPath()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(r.out, strings.Join(walk, "-"))
+			return nil
+		},
+	},
+	"postman": {
+		usage: "postman START",
+		help:  "Find the cheapest closed walk from START using every arc at least once.",
+		run: func(r *repl, args []string) error {
+			if err := needArgs(args, 1, 1); err != nil {
+				return err
+			}
+			walk, weight, err := r.g.FindChinesePostmanTour(args[0])
+			if err != nil {
+				return err
+			}
+			r.printRoute(walk, "weight", weight)
+			return nil
+		},
+	},
+	"tour": {
+		usage: "tour [-heuristic] KEY...",
+		help:  "Find the shortest tour from the first vertex through the others, exactly or with a heuristic.",
+		run: func(r *repl, args []string) error {
+			find := r.g.FindExactTour
+			if len(args) > 0 && args[0] == "-heuristic" {
+				find, args = r.g.FindHeuristicTour, args[1:]
+			}
+			if err := needArgs(args, 1, -1); err != nil {
+				return err
+			}
+			tour, err := find(args)
+			if err != nil {
+				return err
+			}
+			r.printRoute(tour.Route, "weight", tour.Weight)
+			return nil
+		},
+	},
+}
+
+// Return the names of the REPL commands, in order.
+func replCommandNames() []string {
+	var names []string
+	for name := range replCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply an operation to the graph and record it with the changes of the current command.
+func (r *repl) apply(op operation) error {
+	if err := op.do(); err != nil {
+		return err
+	}
+	r.changes = append(r.changes, op)
+	return nil
+}
+
+// Undo the operations of a change, last first.
+func undoChanges(changes []operation) {
+	for i := len(changes) - 1; i >= 0; i-- {
+		changes[i].undo()
+	}
+}
+
+// Return the arc from the vertex 'fromKey' to the vertex 'toKey' inserted last, or nil when there is none.
+func (r *repl) firstArc(fromKey, toKey string) *graph.Arc {
+	vPtr, err := r.vertex(fromKey)
+	if err != nil {
+		return nil
+	}
+	for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+		if aPtr.Dest.Key == toKey {
+			return aPtr
+		}
+	}
+	return nil
+}
+
+// Insert an arc holding every field of 'arc' but its place in the graph.
+func (r *repl) linkArc(fromKey, toKey string, arc graph.Arc) error {
+	if err := r.g.InsertArc(fromKey, toKey, arc.Weight); err != nil {
+		return err
+	}
+	aPtr := r.firstArc(fromKey, toKey)
+	arc.Dest, arc.NextArc = aPtr.Dest, aPtr.NextArc
+	*aPtr = arc
+	return nil
+}
+
+func (r *repl) insertVertex(key string) error {
+	return r.apply(operation{
+		do: func() error {
+			r.g.InsertVertex(key)
+			return nil
+		},
+		undo: func() { r.g.DeleteVertex(key) },
+	})
+}
+
+// Remove a vertex without arcs, keeping its attributes and tree mark to insert it back.
+func (r *repl) removeVertex(key string) error {
+	var removed graph.Vertex
+	return r.apply(operation{
+		do: func() error {
+			vPtr, err := r.vertex(key)
+			if err != nil {
+				return err
+			}
+			removed = *vPtr
+			r.g.DeleteVertex(key)
+			return nil
+		},
+		undo: func() {
+			r.g.InsertVertex(key)
+			vPtr, _ := r.vertex(key)
+			vPtr.InTree, vPtr.Attributes = removed.InTree, removed.Attributes
+		},
+	})
+}
+
+func (r *repl) insertArc(fromKey, toKey string, arc graph.Arc) error {
+	return r.apply(operation{
+		do:   func() error { return r.linkArc(fromKey, toKey, arc) },
+		undo: func() { r.g.DeleteArc(fromKey, toKey) },
+	})
+}
+
+// Remove the arc from the vertex 'fromKey' to the vertex 'toKey' inserted last, keeping a copy to insert it back.
+// Inserted back, the arc is again the last of its parallel arcs.
+func (r *repl) removeArc(fromKey, toKey string) error {
+	var removed graph.Arc
+	return r.apply(operation{
+		do: func() error {
+			aPtr := r.firstArc(fromKey, toKey)
+			if aPtr == nil {
+				return errors.New("no arc from " + fromKey + " to " + toKey)
+			}
+			removed = *aPtr
+			return r.g.DeleteArc(fromKey, toKey)
+		},
+		undo: func() { r.linkArc(fromKey, toKey, removed) },
+	})
+}
+
+func (r *repl) setWeight(fromKey, toKey string, weight float64) error {
+	var old float64
+	return r.apply(operation{
+		do: func() error {
+			aPtr := r.firstArc(fromKey, toKey)
+			if aPtr == nil {
+				return errors.New("no arc from " + fromKey + " to " + toKey)
+			}
+			old, aPtr.Weight = aPtr.Weight, weight
+			return nil
+		},
+		undo: func() { r.firstArc(fromKey, toKey).Weight = old },
+	})
+}
+
+// Replace the whole graph. The graph replaced is kept as it is, to go back to it.
+func (r *repl) replaceGraph(g *graph.Graph) {
+	var old *graph.Graph
+	r.apply(operation{
+		do: func() error {
+			old, r.g = r.g, g
+			return nil
+		},
+		undo: func() { r.g = old },
+	})
+}
+
+func (r *repl) help(args []string) {
+	if len(args) == 1 {
+		if cmd, ok := replCommands[args[0]]; ok {
+			fmt.Fprintf(r.out, "%s\n  %s\n", cmd.usage, cmd.help)
+			return
+		}
+		if cmd, ok := commands[args[0]]; ok {
+			fmt.Fprintf(r.out, "%s\n  as on the command line\n", cmd.usage)
+			return
+		}
+	}
+	fmt.Fprint(r.out, "queries as on the command line:\n")
+	for _, name := range []string{"distance", "shortest", "trips", "roundtrips"} {
+		fmt.Fprintf(r.out, "  %s\n", commands[name].usage)
+	}
+	fmt.Fprint(r.out, "other commands:\n")
+	for _, name := range replCommandNames() {
+		fmt.Fprintf(r.out, "  %s\n", replCommands[name].usage)
+	}
+	fmt.Fprint(r.out, "  help [COMMAND]\n  quit\nKeys with spaces are written in double quotes; tab completes commands and keys.\n")
+}
+
+// Run a query of the command line.
+func (r *repl) query(name string, args []string) error {
+	cmd := commands[name]
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	query := cmd.setup(flags)
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return errors.New("usage: " + cmd.usage)
+		}
+		return err
+	}
+	result, err := query(r.g, positional)
+	if err != nil {
+		return err
+	}
+	writeText(r.out, result)
+	return nil
+}
+
+// Run a command line and return whether the session is over.
+func (r *repl) execute(line string) bool {
+	args, err := splitLine(line)
+	if err != nil || len(args) == 0 {
+		if err != nil {
+			fmt.Fprintln(r.errOut, "error: "+err.Error())
+		}
+		return false
+	}
+	name := args[0]
+	args = args[1:]
+	cmd, ok := replCommands[name]
+	switch {
+	case name == "quit" || name == "exit":
+		return true
+	case name == "help":
+		r.help(args)
+	case commands[name].setup != nil:
+		err = r.query(name, args)
+	case !ok:
+		err = errors.New("unknown command " + strconv.Quote(name) + "; type help for the commands")
+	case cmd.edit:
+		r.changes = nil
+		if err = cmd.run(r, args); err != nil {
+			undoChanges(r.changes) // a failed change leaves the graph as it was
+			break
+		}
+		r.undo = append(r.undo, r.changes)
+		if len(r.undo) > maxUndo {
+			r.undo = r.undo[1:]
+		}
+		r.redo = nil
+	default:
+		err = cmd.run(r, args)
+	}
+	if err == errUsage {
+		err = errors.New("usage: " + cmd.usage)
+	}
+	if err != nil {
+		fmt.Fprintln(r.errOut, "error: "+err.Error())
+	}
+	return false
+}
+
+// Write a graph to the file 'path' in the format 'format', or the format told by its extension when empty.
+func saveGraph(g *graph.Graph, path, format string) error {
+	format, err := graphFormat(path, format)
+	if err != nil {
+		return err
+	}
+	var buffer bytes.Buffer
+	switch format {
+	case "csv":
+		err = g.WriteCSVEdges(&buffer, graph.CSVOptions{})
+	case "json":
+		err = g.WriteJSON(&buffer)
+	case "dot":
+		err = g.WriteDOT(&buffer, graph.DOTOptions{})
+	default:
+		var spec string
+		if spec, err = g.FormatRouteSpec(); err == nil {
+			buffer.WriteString(spec)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, buffer.Bytes(), 0o644)
+}
+
+// Run an interactive session on the graph 'g', read from 'file' unless empty. When 'stdin' is a terminal, lines
+// are edited with completion and history; otherwise they are read as they come, without a prompt.
+func runREPL(g *graph.Graph, file, format string, stdin io.Reader, stdout, stderr io.Writer) int {
+	r := &repl{g: g, file: file, format: format, out: stdout, errOut: stderr}
+	reader := bufio.NewReader(stdin)
+	r.editor = &lineEditor{in: reader, out: stdout, complete: r.complete}
+	terminal, _ := stdin.(*os.File)
+	greeted := false
+	for {
+		var line string
+		var err error
+		var restore func()
+		if terminal != nil {
+			restore, _ = makeRaw(terminal.Fd())
+		}
+		if restore != nil {
+			if !greeted {
+				fmt.Fprint(stdout, "Type help for the commands.\r\n")
+				greeted = true
+			}
+			line, err = r.editor.readLine("graph> ")
+			restore()
+		} else {
+			line, err = reader.ReadString('\n')
+			if err == io.EOF && line != "" {
+				err = nil
+			}
+			line = strings.TrimRight(line, "\r\n")
+		}
+		if err != nil {
+			if err != io.EOF {
+				fmt.Fprintln(stderr, "graph: "+err.Error())
+				return exitInput
+			}
+			return exitOK
+		}
+		r.editor.addHistory(line)
+		if r.execute(line) {
+			return exitOK
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/audathuynh/graph"
+)
+
+// Run a REPL session on the route spec 'spec' with the lines 'lines' and return its outputs.
+func runSession(t *testing.T, spec string, lines ...string) (string, string) {
+	var stdout, stderr strings.Builder
+	args := []string{"repl"}
+	if spec != "" {
+		args = append(args, "-graph", writeFile(t, "routes.txt", spec))
+	}
+	if status := run(args, strings.NewReader(strings.Join(lines, "\n")), &stdout, &stderr); status != exitOK {
+		t.Errorf("Expected the status 0, got %d (%s)", status, stderr.String())
+	}
+	return stdout.String(), stderr.String()
+}
+
+func TestREPLQueries(t *testing.T) {
+	fmt.Println("Testing REPL queries")
+	stdout, stderr := runSession(t, testSpec,
+		"shortest a c",
+		"trips -from c -to c -max-stops 3",
+		"widest a c",
+		"roundtrip c",
+		"neighbours c",
+		"degree c",
+		"shortest a",
+		"fly",
+		`shortest "a`,
+		"shortest a x",
+	)
+	expected := "a-b-c\ndistance 9\n" +
+		"c-d-c\nc-e-b-c\n2 trips\n" +
+		"a-d-c\nwidth 5\n" +
+		"c-e-b-c\ndistance 9\n" +
+		"-> d 8\n-> e 2\n<- b 4\n<- d 8\n" +
+		"in 2  out 2\n"
+	if stdout != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, stdout)
+	}
+	errors := strings.Split(strings.TrimSpace(stderr), "\n")
+	if len(errors) != 4 || !strings.Contains(errors[1], `unknown command "fly"`) ||
+		!strings.Contains(errors[2], "missing closing quote") || !strings.Contains(errors[3], `unknown vertex "x"`) {
+		t.Errorf("Wrong errors:\n%s", stderr)
+	}
+}
+
+func TestREPLEditing(t *testing.T) {
+	fmt.Println("Testing REPL editing and undo")
+	stdout, stderr := runSession(t, testSpec,
+		"add arc a c 2",
+		"shortest a c",
+		"undo",
+		"shortest a c",
+		"redo",
+		"shortest a c",
+		"remove vertex c",
+		"degree d",
+		"undo",
+		"degree d",
+		"weight a b 1",
+		"distance a-b",
+		"remove arc a b",
+		"distance a-b",
+		"add vertex a",
+		"undo",
+		"undo",
+		"undo",
+		"undo",
+		"distance a-b",
+		"redo",
+		"shortest a c",
+		"quit",
+		"undo",
+	)
+	expected := "a-c\ndistance 2\n" +
+		"a-b-c\ndistance 9\n" +
+		"a-c\ndistance 2\n" +
+		"in 1  out 1\n" +
+		"in 2  out 2\n" +
+		"a-b\ndistance 1\n" +
+		"a-b\ndistance 5\n" +
+		"a-c\ndistance 2\n"
+	if stdout != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, stdout)
+	}
+	// queries and failed changes cannot be undone, so 3 changes are undone before the graph read from the file
+	errors := strings.Split(strings.TrimSpace(stderr), "\n")
+	if len(errors) != 3 || !strings.Contains(errors[0], "no such route") ||
+		!strings.Contains(errors[1], `vertex "a" already exists`) || !strings.Contains(errors[2], "nothing to undo") {
+		t.Errorf("Wrong errors:\n%s", stderr)
+	}
+
+	stdout, stderr = runSession(t, "",
+		`add vertex "Central Station" b`,
+		`add arc "Central Station" c 3`,
+		`add arc c b`,
+		"vertices",
+		"arcs",
+	)
+	expected = "Central Station  in 0  out 1\nb  in 1  out 0\nc  in 1  out 1\n3 vertices\n" +
+		"Central Station -> c 3\nc -> b 1\n2 arcs\n"
+	if stdout != expected || stderr != "" {
+		t.Errorf("Expected\n%s\ngot\n%s%s", expected, stdout, stderr)
+	}
+}
+
+func TestREPLUndoTimedArcs(t *testing.T) {
+	fmt.Println("Testing REPL undo of timed arcs")
+	var out, errOut strings.Builder
+	r := &repl{g: graph.NewGraph(), out: &out, errOut: &errOut}
+	for _, key := range []string{"a", "b", "c"} {
+		r.g.InsertVertex(key)
+	}
+	travelTime, _ := graph.NewScheduledTime([]float64{10, 20}, []float64{5, 3})
+	r.g.InsertTimedArc("a", "b", travelTime)
+	r.g.InsertArc("a", "b", 15)
+	r.g.InsertArcWithCapacity("b", "c", 4, 2)
+	for _, line := range []string{"remove vertex b", "undo", "redo", "undo", "add vertex c", "weight a b 7", "undo"} {
+		r.execute(line)
+	}
+	if !strings.Contains(errOut.String(), `vertex "c" already exists`) {
+		t.Errorf("Adding an existing vertex should fail:\n%s", errOut.String())
+	}
+	stops, err := r.g.FindEarliestArrival("a", "c", 12)
+	if err != nil || len(stops) != 3 || stops[1].Arrival != 23 || stops[2].Arrival != 25 {
+		t.Errorf("The timed arc should be back, got %v (%v)", stops, err)
+	}
+	var weights []float64
+	for aPtr := r.g.First.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+		weights = append(weights, aPtr.Weight)
+	}
+	if !reflect.DeepEqual(weights, []float64{15, 3}) || r.g.First.NextVertex.Arc.Capacity != 4 {
+		t.Errorf("The arcs should be back in their order, got weights %v", weights)
+	}
+
+	// the arcs of a tree keep their mark
+	r.execute("arborescence a")
+	r.execute("remove vertex b")
+	r.execute("undo")
+	if aPtr := r.g.First.NextVertex.Arc; !aPtr.InTree || !aPtr.Dest.InTree || !r.g.First.NextVertex.InTree {
+		t.Errorf("The arcs and vertices of the arborescence should stay in the tree")
+	}
+}
+
+func TestREPLFiles(t *testing.T) {
+	fmt.Println("Testing REPL load and save")
+	dir := t.TempDir()
+	csvFile := filepath.Join(dir, "routes.csv")
+	specFile := filepath.Join(dir, "routes.txt")
+	_, stderr := runSession(t, testSpec,
+		"save "+csvFile,
+		"add arc a c 2",
+		"save",
+		"new",
+		"save "+specFile,
+		"load "+csvFile,
+		"shortest a c",
+		"load "+filepath.Join(dir, "missing.txt"),
+	)
+	if !strings.Contains(stderr, "missing.txt") || strings.Count(stderr, "\n") != 1 {
+		t.Errorf("Only the missing file should be reported:\n%s", stderr)
+	}
+	data, _ := os.ReadFile(csvFile)
+	if !strings.HasPrefix(string(data), "from,to,weight\na,b,5\na,c,2\n") {
+		t.Errorf("The graph should be saved to the file last saved:\n%s", data)
+	}
+	data, _ = os.ReadFile(specFile)
+	if string(data) != "" {
+		t.Errorf("The empty graph should be saved, got %q", data)
+	}
+}
+
+func TestREPLCompletion(t *testing.T) {
+	fmt.Println("Testing REPL completion")
+	r := &repl{g: graph.NewGraph()}
+	for _, key := range []string{"Central Station", "Coast", `q"x`} {
+		r.g.InsertVertex(key)
+	}
+	for _, test := range []struct {
+		line       string
+		start      int
+		candidates []string
+	}{
+		{"sh", 0, []string{"shortest"}},
+		{"re", 0, []string{"redo", "reliable", "remove"}},
+		{"add ", 4, []string{"arc", "vertex"}},
+		{"allpairs w", 9, []string{"widest"}},
+		{"shortest C", 9, []string{`"Central Station"`, "Coast"}},
+		{`shortest "Cen`, 9, []string{`"Central Station"`}},
+		{"shortest Coast ", 15, []string{`"Central Station"`, `"q\"x"`, "Coast"}},
+		{"save C", 5, nil},
+		{"trips -fr", 6, nil},
+	} {
+		start, candidates := r.complete([]rune(test.line), len([]rune(test.line)))
+		if start != test.start || !reflect.DeepEqual(candidates, test.candidates) {
+			t.Errorf("%q: expected %d %v, got %d %v", test.line, test.start, test.candidates, start, candidates)
+		}
+	}
+	words, err := splitLine(`add  arc "a \"b\" \\c" d`)
+	if err != nil || !reflect.DeepEqual(words, []string{"add", "arc", `a "b" \c`, "d"}) {
+		t.Errorf("Wrong words %q", words)
+	}
+}
+
+func TestREPLAllPairs(t *testing.T) {
+	fmt.Println("Testing REPL all-pairs tables")
+	stdout, stderr := runSession(t, "ab5, bc4, ca2, ad1\n",
+		"allpairs",
+		"allpairs widest",
+		"allpairs fastest",
+	)
+	expected := "" +
+		"     a  b  c  d\n" +
+		"  a  0  5  9  1\n" +
+		"  b  6  0  4  7\n" +
+		"  c  2  7  0  3\n" +
+		"  d  -  -  -  0\n" +
+		"        a     b     c     d\n" +
+		"  a  +Inf     5     4     1\n" +
+		"  b     2  +Inf     4     1\n" +
+		"  c     2     2  +Inf     1\n" +
+		"  d     -     -     -  +Inf\n"
+	if stdout != expected || !strings.Contains(stderr, `unknown path algebra "fastest"`) {
+		t.Errorf("Expected\n%s\ngot\n%s%s", expected, stdout, stderr)
+	}
+}
+
+func TestREPLCapacities(t *testing.T) {
+	fmt.Println("Testing REPL capacities")
+	stdout, stderr := runSession(t, "",
+		"add vertex s m t",
+		"add arc s m 1 10",
+		"add arc m t 1 4",
+		"add arc s t 2 3",
+		"add arc t m 5 0",
+		"arcs",
+		"neighbours m",
+		"maxflow s t",
+	)
+	expected := "m -> t 1  capacity 4\ns -> m 1  capacity 10\ns -> t 2  capacity 3\nt -> m 5  capacity 0\n4 arcs\n" +
+		"-> t 1  capacity 4\n<- s 1  capacity 10\n<- t 5  capacity 0\n" +
+		"m -> t 4/4\ns -> m 4/10\ns -> t 3/3\ncut m -> t\ncut s -> t\nflow 7\n"
+	if stdout != expected || stderr != "" {
+		t.Errorf("Expected\n%s\ngot\n%s%s", expected, stdout, stderr)
+	}
+}
@@ -0,0 +1,309 @@
+package graph
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CSVError reports a malformed row of a CSV document. Lines start at 1 with the header.
+type CSVError struct {
+	Line    int
+	Column  string // header of the offending column, if any
+	Message string
+}
+
+func (err *CSVError) Error() string {
+	if err.Column != "" {
+		return fmt.Sprintf("line %d, column %s: %s", err.Line, err.Column, err.Message)
+	}
+	return fmt.Sprintf("line %d: %s", err.Line, err.Message)
+}
+
+// CSVOptions describes the columns of a CSV edge list.
+type CSVOptions struct {
+	FromColumn   string // header of the origins; "from" when empty
+	ToColumn     string // header of the destinations; "to" when empty
+	WeightColumn string // header of the weights; "weight" when empty
+	Comma        rune   // field delimiter; ',' when 0
+
+	// AttributeColumns maps the headers of the other columns to the names of the arc attributes they fill. When nil,
+	// every other column fills the attribute named after its header. Columns left out of a non-nil map are ignored.
+	AttributeColumns map[string]string
+}
+
+func (options CSVOptions) withDefaults() CSVOptions {
+	if options.FromColumn == "" {
+		options.FromColumn = "from"
+	}
+	if options.ToColumn == "" {
+		options.ToColumn = "to"
+	}
+	if options.WeightColumn == "" {
+		options.WeightColumn = "weight"
+	}
+	if options.Comma == 0 {
+		options.Comma = ','
+	}
+	return options
+}
+
+// Read a CSV edge list whose first row is a header. Headers are matched without regard to case and surrounding
+// spaces. Every row is an arc from the vertex in the from column to the vertex in the to column; a row with an
+// empty to column adds a vertex without an arc. A missing weight column or an empty weight means a weight of 1.
+// The other columns become string attributes of the arcs as set by options.AttributeColumns; empty cells are left
+// out. Errors are reported with a *CSVError.
+func ReadCSVEdges(r io.Reader, options CSVOptions) (*Graph, error) {
+	options = options.withDefaults()
+	reader := csv.NewReader(r)
+	reader.Comma = options.Comma
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, &CSVError{Line: 1, Message: "missing header"}
+		}
+		return nil, csvReadError(err)
+	}
+	from, to, weight := -1, -1, -1
+	attributes := make(map[int]string)
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		switch {
+		case strings.EqualFold(name, options.FromColumn):
+			from = i
+		case strings.EqualFold(name, options.ToColumn):
+			to = i
+		case strings.EqualFold(name, options.WeightColumn):
+			weight = i
+		case options.AttributeColumns == nil:
+			attributes[i] = name
+		default:
+			for column, attribute := range options.AttributeColumns {
+				if strings.EqualFold(name, column) {
+					attributes[i] = attribute
+				}
+			}
+		}
+	}
+	if from == -1 {
+		return nil, &CSVError{Line: 1, Column: options.FromColumn, Message: "missing column"}
+	}
+	if to == -1 {
+		return nil, &CSVError{Line: 1, Column: options.ToColumn, Message: "missing column"}
+	}
+
+	builder := newGraphBuilder()
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, csvReadError(err)
+		}
+		line, _ := reader.FieldPos(0)
+		cell := func(i int) string {
+			if i >= 0 && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		if cell(from) == "" {
+			if len(row) == 1 && row[0] == "" {
+				continue // a blank line
+			}
+			return nil, &CSVError{Line: line, Column: header[from], Message: "empty vertex key"}
+		}
+		fromPtr := builder.vertex(cell(from))
+		if cell(to) == "" {
+			continue
+		}
+		w := 1.0
+		if text := cell(weight); text != "" {
+			if w, err = strconv.ParseFloat(text, 64); err != nil {
+				return nil, &CSVError{Line: line, Column: header[weight], Message: "invalid weight " + strconv.Quote(text)}
+			}
+		}
+		aPtr := linkArc(fromPtr, builder.vertex(cell(to)), w)
+		for i, name := range attributes {
+			if value := cell(i); value != "" {
+				if aPtr.Attributes == nil {
+					aPtr.Attributes = make(Attributes)
+				}
+				aPtr.Attributes[name] = value
+			}
+		}
+	}
+	return builder.graph, nil
+}
+
+// Turn an error of the CSV reader into a CSVError.
+func csvReadError(err error) error {
+	if parseErr, ok := err.(*csv.ParseError); ok {
+		return &CSVError{Line: parseErr.Line, Message: parseErr.Err.Error()}
+	}
+	return err
+}
+
+// Write the graph as a CSV edge list with the columns from, to and weight, named as in 'options', followed by a
+// column for every arc attribute in name order. Vertices without arcs are written as rows with an empty to
+// column, so ReadCSVEdges reads back the same graph, with attributes as strings.
+func (graph *Graph) WriteCSVEdges(w io.Writer, options CSVOptions) error {
+	options = options.withDefaults()
+	names, _, err := graph.attributeKinds(true)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		for _, column := range []string{options.FromColumn, options.ToColumn, options.WeightColumn} {
+			if strings.EqualFold(name, column) {
+				return errors.New("Arc attribute " + name + " clashes with the column " + column)
+			}
+		}
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = options.Comma
+	writer.Write(append([]string{options.FromColumn, options.ToColumn, options.WeightColumn}, names...))
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		if vPtr.Arc == nil && vPtr.InDegree == 0 {
+			writer.Write([]string{vPtr.Key, "", ""})
+		}
+		for _, aPtr := range vPtr.arcsInInsertionOrder() {
+			row := []string{vPtr.Key, aPtr.Dest.Key, strconv.FormatFloat(aPtr.Weight, 'g', -1, 64)}
+			for _, name := range names {
+				value := ""
+				if v, ok := aPtr.Attributes[name]; ok {
+					value = formatAttribute(v)
+				}
+				row = append(row, value)
+			}
+			writer.Write(row)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// MatrixOptions describes a CSV adjacency matrix.
+type MatrixOptions struct {
+	// NoArc is the cell value meaning that there is no arc, "" (an empty cell) by default. It is compared as text,
+	// so it never conflicts with a weight such as math.MaxFloat64, which FindShortestRoute uses for infinity.
+	NoArc string
+	Comma rune // field delimiter; ',' when 0
+}
+
+// Read a CSV adjacency matrix. The first row holds an empty corner cell followed by the keys of the vertices;
+// every other row holds the key of a vertex followed by the weights of its arcs to the vertices of the header.
+// Rows may come in any order, but every vertex of the header must have exactly one row. Cells equal to
+// options.NoArc mean that there is no arc. Errors are reported with a *CSVError.
+func ReadCSVMatrix(r io.Reader, options MatrixOptions) (*Graph, error) {
+	if options.Comma == 0 {
+		options.Comma = ','
+	}
+	reader := csv.NewReader(r)
+	reader.Comma = options.Comma
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, &CSVError{Line: 1, Message: "missing header"}
+		}
+		return nil, csvReadError(err)
+	}
+	keys := header[1:]
+	for i := range keys {
+		keys[i] = strings.TrimSpace(keys[i])
+	}
+	builder := newGraphBuilder()
+	index := make(map[string]int)
+	for i, key := range keys {
+		if _, ok := index[key]; ok || key == "" {
+			return nil, &CSVError{Line: 1, Column: key, Message: "empty or duplicate vertex key"}
+		}
+		index[key] = i
+		builder.vertex(key)
+	}
+	type matrixRow struct {
+		line  int
+		cells []string
+	}
+	rows := make(map[string]matrixRow)
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, csvReadError(err)
+		}
+		line, _ := reader.FieldPos(0)
+		key := strings.TrimSpace(row[0])
+		if _, ok := index[key]; !ok {
+			return nil, &CSVError{Line: line, Message: "vertex " + strconv.Quote(key) + " is not in the header"}
+		}
+		if _, ok := rows[key]; ok {
+			return nil, &CSVError{Line: line, Message: "duplicate row for the vertex " + strconv.Quote(key)}
+		}
+		rows[key] = matrixRow{line: line, cells: row[1:]}
+	}
+	// arcs are inserted in key order so that the graph does not depend on the order of the rows
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	for _, key := range sorted {
+		row, ok := rows[key]
+		if !ok {
+			return nil, &CSVError{Line: 1, Column: key, Message: "no row for the vertex"}
+		}
+		for i, text := range row.cells {
+			if text = strings.TrimSpace(text); text == options.NoArc {
+				continue
+			}
+			weight, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, &CSVError{Line: row.line, Column: keys[i], Message: "invalid weight " + strconv.Quote(text)}
+			}
+			linkArc(builder.vertices[key], builder.vertices[keys[i]], weight)
+		}
+	}
+	return builder.graph, nil
+}
+
+// Write the graph as a CSV adjacency matrix with the vertices in key order. Cells without an arc hold
+// options.NoArc. The graph must not have parallel arcs, and no weight may be written as options.NoArc.
+func (graph *Graph) WriteCSVMatrix(w io.Writer, options MatrixOptions) error {
+	if options.Comma == 0 {
+		options.Comma = ','
+	}
+	vertices, index := graph.indexVertices()
+	writer := csv.NewWriter(w)
+	writer.Comma = options.Comma
+	header := []string{""}
+	for _, vPtr := range vertices {
+		header = append(header, vPtr.Key)
+	}
+	writer.Write(header)
+	for _, vPtr := range vertices {
+		row := make([]string, len(vertices)+1)
+		row[0] = vPtr.Key
+		for i := range vertices {
+			row[i+1] = options.NoArc
+		}
+		for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			if aPtr.NextArc != nil && aPtr.NextArc.Dest == aPtr.Dest {
+				return errors.New("Parallel arcs " + vPtr.Key + "-" + aPtr.Dest.Key + " cannot be written in a matrix")
+			}
+			text := strconv.FormatFloat(aPtr.Weight, 'g', -1, 64)
+			if text == options.NoArc {
+				return errors.New("Weight of the arc " + vPtr.Key + "-" + aPtr.Dest.Key + " cannot be told apart from no arc")
+			}
+			row[index[aPtr.Dest]+1] = text
+		}
+		writer.Write(row)
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,107 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSVEdges(t *testing.T) {
+	fmt.Println("Testing CSV edge list import")
+	document := "Segment Start; Segment End ;Length;Track;Notes\n" +
+		"a;b;5;T1;\n" +
+		"b;c;4.5;T2;single track\n" +
+		"c;a;;;\n" +
+		"\n" +
+		"d;;;;\n"
+	g, err := graph.ReadCSVEdges(strings.NewReader(document), graph.CSVOptions{
+		FromColumn: "segment start", ToColumn: "segment end", WeightColumn: "length", Comma: ';',
+		AttributeColumns: map[string]string{"track": "line"},
+	})
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	distance, err := g.FindDistance([]string{"a", "b", "c", "a"})
+	if err != nil || distance != 10.5 || g.Count != 4 {
+		t.Errorf("The distance should be 10.5, not %v", distance)
+	}
+	if !reflect.DeepEqual(g.First.Arc.Attributes, graph.Attributes{"line": "T1"}) || g.First.NextVertex.NextVertex.Arc.Attributes != nil {
+		t.Errorf("Mapped columns should become attributes")
+	}
+
+	_, err = graph.ReadCSVEdges(strings.NewReader("from,to,weight\na,b,1\nb,c,x\n"), graph.CSVOptions{})
+	csvErr, ok := err.(*graph.CSVError)
+	if !ok || csvErr.Line != 3 || csvErr.Column != "weight" {
+		t.Errorf("Error should be at line 3, column weight: %v", err)
+	}
+	_, err = graph.ReadCSVEdges(strings.NewReader("source,to\n"), graph.CSVOptions{})
+	if csvErr, ok := err.(*graph.CSVError); !ok || csvErr.Column != "from" {
+		t.Errorf("A missing column should be reported: %v", err)
+	}
+}
+
+func TestCSVEdgesRoundTrip(t *testing.T) {
+	fmt.Println("Testing CSV edge list round trips")
+	g := graph.NewGraph()
+	initGraph(g)
+	g.InsertVertex("f")
+	g.InsertArc("a", "b", 1)
+	g.First.Arc.Attributes = graph.Attributes{"line": "red", "note": "a, \"quoted\" note"}
+	var out strings.Builder
+	if err := g.WriteCSVEdges(&out, graph.CSVOptions{}); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	if !strings.HasPrefix(out.String(), "from,to,weight,line,note\na,b,5,,\na,b,1,red,\"a, \"\"quoted\"\" note\"\n") {
+		t.Errorf("Unexpected CSV:\n%s", out.String())
+	}
+	parsed, err := graph.ReadCSVEdges(strings.NewReader(out.String()), graph.CSVOptions{})
+	if err != nil || !reflect.DeepEqual(parsed, g) {
+		t.Errorf("The CSV should be read back into the same graph: %v", err)
+	}
+}
+
+func TestCSVMatrix(t *testing.T) {
+	fmt.Println("Testing CSV adjacency matrices")
+	g := graph.NewGraph()
+	initGraph(g)
+	g.InsertArc("e", "e", math.MaxFloat64)
+	g.InsertArc("b", "a", 0)
+	var out strings.Builder
+	if err := g.WriteCSVMatrix(&out, graph.MatrixOptions{NoArc: "-"}); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	expected := ",a,b,c,d,e\n" +
+		"a,-,5,-,5,7\n" +
+		"b,0,-,4,-,-\n" +
+		"c,-,-,-,8,2\n" +
+		"d,-,-,8,-,6\n" +
+		"e,-,3,-,-,1.7976931348623157e+308\n"
+	if out.String() != expected {
+		t.Errorf("The matrix should be\n%s\nnot\n%s", expected, out.String())
+	}
+	parsed, err := graph.ReadCSVMatrix(strings.NewReader(out.String()), graph.MatrixOptions{NoArc: "-"})
+	if err != nil || !reflect.DeepEqual(parsed, g) {
+		t.Errorf("The matrix should be read back into the same graph: %v", err)
+	}
+	if err := g.WriteCSVMatrix(&out, graph.MatrixOptions{NoArc: "0"}); err == nil {
+		t.Errorf("A weight equal to the sentinel should be an error")
+	}
+
+	reordered := ",x,y\ny,1,\nx,,2\n"
+	parsed, err = graph.ReadCSVMatrix(strings.NewReader(reordered), graph.MatrixOptions{})
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+	} else if d, err := parsed.FindDistance([]string{"x", "y", "x"}); err != nil || d != 3 {
+		t.Errorf("The distance should be 3, not %v", d)
+	}
+	_, err = graph.ReadCSVMatrix(strings.NewReader(",x,y\nx,1,\n"), graph.MatrixOptions{})
+	if csvErr, ok := err.(*graph.CSVError); !ok || csvErr.Column != "y" {
+		t.Errorf("A missing row should be reported: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package graph
+
+import (
+	"iter"
+	"math"
+)
+
+// CycleLimits caps the cycles returned by SimpleCycles. A zero value means no limit.
+type CycleLimits struct {
+	MaxLength int     // max number of arcs in a cycle
+	MaxWeight float64 // max total weight of a cycle
+}
+
+// Return every elementary cycle of the graph with Johnson's algorithm. A cycle is returned as a round trip
+// such as [c d e c], starting from its vertex that comes first in the vertex list. Cycles are returned in the
+// order of their start vertices. Parallel arcs are considered once, using the cheapest of them.
+// When limits are given, the search is a depth-first search bounded by the limits instead.
+func (graph *Graph) SimpleCycles(limits CycleLimits) iter.Seq[[]string] {
+	return func(yield func([]string) bool) {
+		vertices, index := graph.indexVertices()
+		n := len(vertices)
+		adjacent, weights := cheapestNeighbours(vertices, index)
+		negative := false
+		for _, row := range weights {
+			for _, w := range row {
+				if w < 0 {
+					negative = true
+				}
+			}
+		}
+		bounded := limits.MaxLength > 0 || limits.MaxWeight > 0
+
+		inComponent := make([]bool, n)
+		blocked := make([]bool, n)
+		blockedBy := make([]map[int]bool, n)
+		onStack := make([]bool, n)
+		var stack []int
+		stopped := false
+
+		emit := func(s int) {
+			cycle := make([]string, 0, len(stack)+1)
+			for _, v := range stack {
+				cycle = append(cycle, vertices[v].Key)
+			}
+			cycle = append(cycle, vertices[s].Key)
+			if !yield(cycle) {
+				stopped = true
+			}
+		}
+		var unblock func(v int)
+		unblock = func(v int) {
+			blocked[v] = false
+			for w := range blockedBy[v] {
+				delete(blockedBy[v], w)
+				if blocked[w] {
+					unblock(w)
+				}
+			}
+		}
+		// Johnson's circuit search. Return true when a cycle through 'v' is found.
+		var circuit func(s, v int) bool
+		circuit = func(s, v int) bool {
+			found := false
+			stack = append(stack, v)
+			blocked[v] = true
+			for _, w := range adjacent[v] {
+				if stopped {
+					break
+				}
+				if !inComponent[w] {
+					continue
+				}
+				if w == s {
+					emit(s)
+					found = true
+				} else if !blocked[w] && circuit(s, w) {
+					found = true
+				}
+			}
+			if found {
+				unblock(v)
+			} else {
+				for _, w := range adjacent[v] {
+					if inComponent[w] {
+						blockedBy[w][v] = true
+					}
+				}
+			}
+			stack = stack[:len(stack)-1]
+			return found
+		}
+		// depth-first search bounded by the limits
+		var search func(s, v int, weight float64)
+		search = func(s, v int, weight float64) {
+			stack = append(stack, v)
+			onStack[v] = true
+			for k, w := range adjacent[v] {
+				if stopped {
+					break
+				}
+				if !inComponent[w] {
+					continue
+				}
+				total := weight + weights[v][k]
+				if limits.MaxWeight > 0 && total > limits.MaxWeight && !negative {
+					continue
+				}
+				if w == s {
+					if limits.MaxWeight <= 0 || total <= limits.MaxWeight {
+						emit(s)
+					}
+				} else if !onStack[w] && (limits.MaxLength <= 0 || len(stack) < limits.MaxLength) {
+					search(s, w, total)
+				}
+			}
+			onStack[v] = false
+			stack = stack[:len(stack)-1]
+		}
+
+		for s := 0; s < n && !stopped; s++ {
+			// the strongly connected component of 's' in the subgraph of the vertices from 's' onwards
+			forward := reachable(adjacent, s, s, false)
+			backward := reachable(adjacent, s, s, true)
+			for v := 0; v < n; v++ {
+				inComponent[v] = forward[v] && backward[v]
+				blocked[v] = false
+				blockedBy[v] = make(map[int]bool)
+			}
+			if bounded {
+				search(s, s, 0)
+			} else {
+				circuit(s, s)
+			}
+		}
+	}
+}
+
+// Return the distinct successors of every vertex together with the weight of the cheapest arc to each of them.
+func cheapestNeighbours(vertices []*Vertex, index map[*Vertex]int) ([][]int, [][]float64) {
+	adjacent := make([][]int, len(vertices))
+	weights := make([][]float64, len(vertices))
+	for i, v := range vertices {
+		position := make(map[int]int)
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			j := index[aPtr.Dest]
+			if k, ok := position[j]; ok {
+				weights[i][k] = math.Min(weights[i][k], aPtr.Weight)
+				continue
+			}
+			position[j] = len(adjacent[i])
+			adjacent[i] = append(adjacent[i], j)
+			weights[i] = append(weights[i], aPtr.Weight)
+		}
+	}
+	return adjacent, weights
+}
+
+// Return the vertices reachable from 'start' (or reaching 'start' when 'reverse' is true)
+// using only the vertices from 'least' onwards.
+func reachable(adjacent [][]int, start, least int, reverse bool) []bool {
+	n := len(adjacent)
+	seen := make([]bool, n)
+	if reverse {
+		predecessors := make([][]int, n)
+		for v := least; v < n; v++ {
+			for _, w := range adjacent[v] {
+				predecessors[w] = append(predecessors[w], v)
+			}
+		}
+		adjacent = predecessors
+	}
+	seen[start] = true
+	queue := []int{start}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, w := range adjacent[v] {
+			if w >= least && !seen[w] {
+				seen[w] = true
+				queue = append(queue, w)
+			}
+		}
+	}
+	return seen
+}
@@ -0,0 +1,54 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"testing"
+)
+
+func TestSimpleCycles(t *testing.T) {
+	fmt.Println("Testing elementary cycles")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	var result [][]string
+	for cycle := range g.SimpleCycles(graph.CycleLimits{}) {
+		result = append(result, cycle)
+	}
+	fmt.Println(result)
+	expected := [][]string{
+		{"b", "c", "d", "e", "b"},
+		{"b", "c", "e", "b"},
+		{"c", "d", "c"}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Solution is not correct")
+	}
+}
+
+func TestSimpleCyclesWithLimits(t *testing.T) {
+	fmt.Println("Testing elementary cycles with limits")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	var result [][]string
+	for cycle := range g.SimpleCycles(graph.CycleLimits{MaxLength: 3}) {
+		result = append(result, cycle)
+	}
+	if !reflect.DeepEqual(result, [][]string{{"b", "c", "e", "b"}, {"c", "d", "c"}}) {
+		t.Errorf("Cycles should have at most 3 arcs")
+	}
+	result = nil
+	for cycle := range g.SimpleCycles(graph.CycleLimits{MaxWeight: 10}) {
+		result = append(result, cycle)
+	}
+	if !reflect.DeepEqual(result, [][]string{{"b", "c", "e", "b"}}) {
+		t.Errorf("Cycles should weigh at most 10")
+	}
+	for cycle := range g.SimpleCycles(graph.CycleLimits{}) {
+		if len(cycle) != 5 {
+			t.Errorf("The iteration should stop after the first cycle")
+		}
+		break
+	}
+}
@@ -0,0 +1,189 @@
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DiagramOptions selects what a Mermaid or PlantUML diagram shows.
+type DiagramOptions struct {
+	CenterKey string   // when set, only the vertices within Hops arcs of this vertex are drawn, whatever their direction
+	Hops      int      // radius of the subgraph around CenterKey
+	Route     []string // route to highlight, e.g. the result of a Find* method
+	Color     string   // color of the highlighted route; "red" when empty
+}
+
+// Return the vertices drawn in a diagram, in key order, with their positions among them.
+func (graph *Graph) diagramVertices(options DiagramOptions) ([]*Vertex, map[*Vertex]int, error) {
+	vertices, index := graph.indexVertices()
+	if options.CenterKey == "" {
+		return vertices, index, nil
+	}
+	center := graph.findVertex(options.CenterKey)
+	if center == nil {
+		return nil, nil, errors.New("CenterKey not found")
+	}
+	if options.Hops < 0 {
+		return nil, nil, errors.New("Hops must not be negative")
+	}
+	// breadth-first search ignoring the direction of the arcs
+	neighbours := undirectedNeighbours(vertices, index)
+	hops := make([]int, len(vertices))
+	for i := range hops {
+		hops[i] = -1
+	}
+	hops[index[center]] = 0
+	queue := []int{index[center]}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		if hops[i] == options.Hops {
+			continue
+		}
+		for _, j := range neighbours[i] {
+			if hops[j] == -1 {
+				hops[j] = hops[i] + 1
+				queue = append(queue, j)
+			}
+		}
+	}
+	var selected []*Vertex
+	selectedIndex := make(map[*Vertex]int)
+	for i, vPtr := range vertices {
+		if hops[i] >= 0 {
+			selectedIndex[vPtr] = len(selected)
+			selected = append(selected, vPtr)
+		}
+	}
+	return selected, selectedIndex, nil
+}
+
+// Return the color of the highlighted route: a color name or a hexadecimal color such as #ff0000.
+func diagramColor(color string) (string, error) {
+	if color == "" {
+		return "red", nil
+	}
+	for i, r := range color {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || (r == '#' && i == 0)) {
+			return "", errors.New("Invalid color " + strconv.Quote(color))
+		}
+	}
+	return color, nil
+}
+
+// Escape text for a quoted Mermaid label. Characters other than letters, digits, spaces and a few punctuation
+// marks are written as entity codes such as #35; so that keys cannot break the syntax.
+func escapeMermaid(text string) string {
+	var out strings.Builder
+	for _, r := range text {
+		if r < 128 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune(" _.,-+:/()", r)) {
+			out.WriteRune(r)
+		} else {
+			out.WriteString("#" + strconv.Itoa(int(r)) + ";")
+		}
+	}
+	return out.String()
+}
+
+// Escape text for a quoted PlantUML name. Quotes, backslashes, tildes and control characters are written as
+// <U+XXXX> code points, which PlantUML shows as the characters themselves.
+func escapePlantUML(text string) string {
+	var out strings.Builder
+	for _, r := range text {
+		if r < ' ' || r == '"' || r == '\\' || r == '~' || r == '<' || r == '>' || r == 0x7f {
+			fmt.Fprintf(&out, "<U+%04X>", r)
+		} else {
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+// Write the graph, or the subgraph selected by 'options', as a Mermaid flowchart. Vertices are drawn as nodes
+// labelled with their keys and arcs as links labelled with their weights. The vertices and arcs of options.Route
+// are highlighted with options.Color.
+func (graph *Graph) WriteMermaid(w io.Writer, options DiagramOptions) error {
+	vertices, index, err := graph.diagramVertices(options)
+	if err != nil {
+		return err
+	}
+	color, err := diagramColor(options.Color)
+	if err != nil {
+		return err
+	}
+	highlighted, onRoute := graph.routeArcs(options.Route)
+	style := "stroke:" + color + ",stroke-width:3px"
+	out := bufio.NewWriter(w)
+	fmt.Fprint(out, "flowchart LR\n")
+	for i, vPtr := range vertices {
+		fmt.Fprintf(out, "    v%d[\"%s\"]\n", i, escapeMermaid(vPtr.Key))
+	}
+	link := 0
+	var highlightedLinks []string
+	for i, vPtr := range vertices {
+		for _, aPtr := range vPtr.arcsInInsertionOrder() {
+			j, ok := index[aPtr.Dest]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(out, "    v%d -->|\"%s\"| v%d\n", i, escapeMermaid(strconv.FormatFloat(aPtr.Weight, 'g', -1, 64)), j)
+			if highlighted[aPtr] {
+				highlightedLinks = append(highlightedLinks, strconv.Itoa(link))
+			}
+			link++
+		}
+	}
+	for i, vPtr := range vertices {
+		if onRoute[vPtr.Key] {
+			fmt.Fprintf(out, "    style v%d %s\n", i, style)
+		}
+	}
+	if len(highlightedLinks) > 0 {
+		fmt.Fprintf(out, "    linkStyle %s %s\n", strings.Join(highlightedLinks, ","), style)
+	}
+	return out.Flush()
+}
+
+// Write the graph, or the subgraph selected by 'options', as a PlantUML diagram. Vertices are drawn as rectangles
+// labelled with their keys and arcs as arrows labelled with their weights. The vertices and arcs of options.Route
+// are drawn in bold with options.Color.
+func (graph *Graph) WritePlantUML(w io.Writer, options DiagramOptions) error {
+	vertices, index, err := graph.diagramVertices(options)
+	if err != nil {
+		return err
+	}
+	color, err := diagramColor(options.Color)
+	if err != nil {
+		return err
+	}
+	color = strings.TrimPrefix(color, "#")
+	highlighted, onRoute := graph.routeArcs(options.Route)
+	out := bufio.NewWriter(w)
+	fmt.Fprint(out, "@startuml\n")
+	for i, vPtr := range vertices {
+		fmt.Fprintf(out, "rectangle \"%s\" as v%d", escapePlantUML(vPtr.Key), i)
+		if onRoute[vPtr.Key] {
+			fmt.Fprintf(out, " #line:%s;line.bold", color)
+		}
+		fmt.Fprint(out, "\n")
+	}
+	for i, vPtr := range vertices {
+		for _, aPtr := range vPtr.arcsInInsertionOrder() {
+			j, ok := index[aPtr.Dest]
+			if !ok {
+				continue
+			}
+			arrow := "-->"
+			if highlighted[aPtr] {
+				arrow = "-[#" + color + ",bold]->"
+			}
+			fmt.Fprintf(out, "v%d %s v%d : %s\n", i, arrow, j, strconv.FormatFloat(aPtr.Weight, 'g', -1, 64))
+		}
+	}
+	fmt.Fprint(out, "@enduml\n")
+	return out.Flush()
+}
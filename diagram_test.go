@@ -0,0 +1,85 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"strings"
+	"testing"
+)
+
+func TestWriteMermaid(t *testing.T) {
+	fmt.Println("Testing Mermaid export")
+	g := graph.NewGraph()
+	initGraph(g)
+	route, _ := g.FindShortestRoute("a", "c")
+	var out strings.Builder
+	if err := g.WriteMermaid(&out, graph.DiagramOptions{Route: route}); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	for _, line := range []string{
+		"flowchart LR",
+		`    v0["a"]`,
+		`    v0 -->|"5"| v1`,
+		`    v4 -->|"3"| v1`,
+		"    style v0 stroke:red,stroke-width:3px",
+		"    style v2 stroke:red,stroke-width:3px",
+		"    linkStyle 0,3 stroke:red,stroke-width:3px",
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("The diagram should contain %s:\n%s", line, out.String())
+		}
+	}
+
+	g = graph.NewGraph()
+	g.InsertVertex(`x"]; click`)
+	g.InsertVertex("y#1")
+	g.InsertArc(`x"]; click`, "y#1", 1)
+	out.Reset()
+	g.WriteMermaid(&out, graph.DiagramOptions{})
+	if !strings.Contains(out.String(), `v0["x#34;#93;#59; click"]`) || !strings.Contains(out.String(), `v1["y#35;1"]`) {
+		t.Errorf("Keys should be escaped:\n%s", out.String())
+	}
+	if err := g.WriteMermaid(&out, graph.DiagramOptions{Color: "red;x"}); err == nil {
+		t.Errorf("An invalid color should be an error")
+	}
+}
+
+func TestWritePlantUMLSubgraph(t *testing.T) {
+	fmt.Println("Testing PlantUML export of a subgraph")
+	g := graph.NewGraph()
+	initGraph(g)
+	g.InsertVertex("f")
+	g.InsertVertex("g")
+	g.InsertArc("f", "g", 1)
+	var out strings.Builder
+	err := g.WritePlantUML(&out, graph.DiagramOptions{CenterKey: "c", Hops: 1, Route: []string{"b", "c", "e"}, Color: "#00ff00"})
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	expected := "@startuml\n" +
+		"rectangle \"b\" as v0 #line:00ff00;line.bold\n" +
+		"rectangle \"c\" as v1 #line:00ff00;line.bold\n" +
+		"rectangle \"d\" as v2\n" +
+		"rectangle \"e\" as v3 #line:00ff00;line.bold\n" +
+		"v0 -[#00ff00,bold]-> v1 : 4\n" +
+		"v1 --> v2 : 8\n" +
+		"v1 -[#00ff00,bold]-> v3 : 2\n" +
+		"v2 --> v1 : 8\n" +
+		"v2 --> v3 : 6\n" +
+		"v3 --> v0 : 3\n" +
+		"@enduml\n"
+	if out.String() != expected {
+		t.Errorf("The diagram should be\n%s\nnot\n%s", expected, out.String())
+	}
+
+	out.Reset()
+	g.WritePlantUML(&out, graph.DiagramOptions{CenterKey: "f"})
+	if out.String() != "@startuml\nrectangle \"f\" as v0\n@enduml\n" {
+		t.Errorf("Zero hops should draw the center only:\n%s", out.String())
+	}
+	if err := g.WritePlantUML(&out, graph.DiagramOptions{CenterKey: "z"}); err == nil {
+		t.Errorf("An unknown center should be an error")
+	}
+}
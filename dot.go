@@ -0,0 +1,427 @@
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DOTOptions controls how a graph is written in the Graphviz DOT language.
+type DOTOptions struct {
+	Name          string   // name of the digraph; "G" when empty
+	Route         []string // route to highlight, e.g. the result of FindShortestRoute
+	HighlightTree bool     // highlight the arcs whose InTree flag is set
+	Color         string   // color of highlighted vertices and arcs; "red" when empty
+}
+
+// DOTError reports where a DOT document could not be read. Lines and columns start at 1.
+type DOTError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (err *DOTError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", err.Line, err.Column, err.Message)
+}
+
+// Quote a DOT identifier. In DOT, '"' is the only character escaped in quoted strings.
+func quoteDOT(id string) string {
+	return `"` + strings.ReplaceAll(id, `"`, `\"`) + `"`
+}
+
+// Check that a DOT identifier is read back as written once quoted. As backslashes are not escaped, a backslash
+// ending the identifier would escape the closing '"', and one before a line break would continue the line.
+func checkDOTID(id string) error {
+	if strings.HasSuffix(id, `\`) || strings.Contains(id, "\\\n") {
+		return errors.New("Identifier " + strconv.Quote(id) + " cannot be written in DOT")
+	}
+	return nil
+}
+
+// Write the graph as a DOT digraph with a node for every vertex and an edge for every arc labelled with its weight.
+// The arcs along options.Route and, when options.HighlightTree is set, the arcs in the tree are drawn in bold
+// with options.Color. Along the route, the first arc between two consecutive vertices is highlighted, as in FindDistance.
+// Keys ending with a backslash cannot be written.
+func (graph *Graph) WriteDOT(w io.Writer, options DOTOptions) error {
+	name, color := options.Name, options.Color
+	if name == "" {
+		name = "G"
+	}
+	if color == "" {
+		color = "red"
+	}
+	for _, id := range []string{name, color} {
+		if err := checkDOTID(id); err != nil {
+			return err
+		}
+	}
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		if err := checkDOTID(vPtr.Key); err != nil {
+			return err
+		}
+	}
+	highlighted, onRoute := graph.routeArcs(options.Route)
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "digraph %s {\n", quoteDOT(name))
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		if onRoute[vPtr.Key] {
+			fmt.Fprintf(out, "\t%s [color=%s, penwidth=2];\n", quoteDOT(vPtr.Key), quoteDOT(color))
+		} else {
+			fmt.Fprintf(out, "\t%s;\n", quoteDOT(vPtr.Key))
+		}
+	}
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		for _, aPtr := range vPtr.arcsInInsertionOrder() {
+			label := strconv.FormatFloat(aPtr.Weight, 'f', -1, 64)
+			fmt.Fprintf(out, "\t%s -> %s [label=%s", quoteDOT(vPtr.Key), quoteDOT(aPtr.Dest.Key), quoteDOT(label))
+			if highlighted[aPtr] || (options.HighlightTree && aPtr.InTree) {
+				fmt.Fprintf(out, ", color=%s, penwidth=2", quoteDOT(color))
+			}
+			fmt.Fprint(out, "];\n")
+		}
+	}
+	fmt.Fprint(out, "}\n")
+	return out.Flush()
+}
+
+// Return the arcs along a route and the keys of its vertices. Between two consecutive vertices the first arc is
+// taken, as in FindDistance; vertices that are not in the graph are skipped.
+func (graph *Graph) routeArcs(route []string) (map[*Arc]bool, map[string]bool) {
+	arcs := make(map[*Arc]bool)
+	keys := make(map[string]bool)
+	for i, key := range route {
+		keys[key] = true
+		if i == 0 {
+			continue
+		}
+		vPtr := graph.findVertex(route[i-1])
+		if vPtr == nil {
+			continue
+		}
+		for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			if aPtr.Dest.Key == key {
+				arcs[aPtr] = true
+				break
+			}
+		}
+	}
+	return arcs, keys
+}
+
+// A token of a DOT document.
+type dotToken struct {
+	text   string // the identifier, or the punctuation such as "{" or "->"
+	id     bool   // whether the token is an identifier
+	quoted bool   // whether the identifier was a quoted string, which is never a keyword
+	eof    bool
+	line   int
+	column int
+}
+
+// Split a DOT document into tokens, skipping comments and whitespace.
+func tokenizeDOT(document string) ([]dotToken, error) {
+	runes := []rune(document)
+	var tokens []dotToken
+	line, column := 1, 1
+	i := 0
+	advance := func() {
+		if runes[i] == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+		i++
+	}
+	for i < len(runes) {
+		r := runes[i]
+		startLine, startColumn := line, column
+		switch {
+		case unicode.IsSpace(r):
+			advance()
+		case r == '#' && column == 1, r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				advance()
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			advance()
+			advance()
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				advance()
+			}
+			if i == len(runes) {
+				return nil, &DOTError{Line: startLine, Column: startColumn, Message: "unterminated comment"}
+			}
+			advance()
+			advance()
+		case r == '"':
+			advance()
+			var text strings.Builder
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' && i+1 < len(runes) && runes[i+1] == '"' {
+					advance()
+				} else if runes[i] == '\\' && i+1 < len(runes) && runes[i+1] == '\n' {
+					advance() // a line continuation
+					advance()
+					continue
+				}
+				text.WriteRune(runes[i])
+				advance()
+			}
+			if i == len(runes) {
+				return nil, &DOTError{Line: startLine, Column: startColumn, Message: "unterminated string"}
+			}
+			advance()
+			tokens = append(tokens, dotToken{text: text.String(), id: true, quoted: true, line: startLine, column: startColumn})
+		case r == '-' && i+1 < len(runes) && (runes[i+1] == '>' || runes[i+1] == '-'):
+			advance()
+			advance()
+			tokens = append(tokens, dotToken{text: string(runes[i-2 : i]), line: startLine, column: startColumn})
+		case strings.ContainsRune("{}[];,=:", r):
+			advance()
+			tokens = append(tokens, dotToken{text: string(r), line: startLine, column: startColumn})
+		case r == '_' || r == '.' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '.' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) ||
+				(i == start && runes[i] == '-')) {
+				advance()
+			}
+			tokens = append(tokens, dotToken{text: string(runes[start:i]), id: true, line: startLine, column: startColumn})
+		default:
+			return nil, &DOTError{Line: startLine, Column: startColumn, Message: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	return append(tokens, dotToken{eof: true, line: line, column: column}), nil
+}
+
+// A parser of the DOT subset read by ReadDOT.
+type dotParser struct {
+	tokens []dotToken
+	pos    int
+}
+
+func (p *dotParser) peek() dotToken {
+	return p.tokens[p.pos]
+}
+
+func (p *dotParser) next() dotToken {
+	token := p.tokens[p.pos]
+	if !token.eof {
+		p.pos++
+	}
+	return token
+}
+
+// Check whether the next token is the punctuation 'text'.
+func (p *dotParser) at(text string) bool {
+	token := p.peek()
+	return !token.eof && !token.id && token.text == text
+}
+
+// Check whether a token is the keyword 'keyword'. Keywords are case-insensitive.
+func isDOTKeyword(token dotToken, keyword string) bool {
+	return token.id && !token.quoted && strings.EqualFold(token.text, keyword)
+}
+
+func (p *dotParser) errorf(token dotToken, format string, args ...interface{}) error {
+	return &DOTError{Line: token.line, Column: token.column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *dotParser) expect(text string) error {
+	if !p.at(text) {
+		return p.errorf(p.peek(), "expected %q, found %s", text, p.describe(p.peek()))
+	}
+	p.next()
+	return nil
+}
+
+func (p *dotParser) describe(token dotToken) string {
+	if token.eof {
+		return "the end of the document"
+	}
+	return strconv.Quote(token.text)
+}
+
+// Read an identifier.
+func (p *dotParser) id() (dotToken, error) {
+	token := p.next()
+	if !token.id {
+		return token, p.errorf(token, "expected an identifier, found %s", p.describe(token))
+	}
+	return token, nil
+}
+
+// Read the attribute lists following a statement, e.g. [label="5", color=red].
+func (p *dotParser) attributes(attrs map[string]dotToken) error {
+	for p.at("[") {
+		p.next()
+		for !p.at("]") {
+			name, err := p.id()
+			if err != nil {
+				return err
+			}
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			value, err := p.id()
+			if err != nil {
+				return err
+			}
+			attrs[name.text] = value
+			if p.at(",") || p.at(";") {
+				p.next()
+			}
+		}
+		p.next()
+	}
+	return nil
+}
+
+// Read a node identifier, skipping a port such as "a":n.
+func (p *dotParser) nodeID() (dotToken, error) {
+	token, err := p.id()
+	if err != nil {
+		return token, err
+	}
+	for p.at(":") {
+		p.next()
+		if _, err := p.id(); err != nil {
+			return token, err
+		}
+	}
+	return token, nil
+}
+
+// Return the weight of an edge from its attributes 'attrs' or the default attributes 'defaults'.
+func dotWeight(attrs, defaults map[string]dotToken) (float64, error) {
+	for _, set := range []map[string]dotToken{attrs, defaults} {
+		for _, name := range []string{"weight", "label"} {
+			if value, ok := set[name]; ok {
+				weight, err := strconv.ParseFloat(strings.TrimSpace(value.text), 64)
+				if err != nil {
+					return 0, &DOTError{Line: value.line, Column: value.column, Message: fmt.Sprintf("invalid weight %q", value.text)}
+				}
+				return weight, nil
+			}
+		}
+	}
+	return 1, nil
+}
+
+// Build a graph from the digraph subset of the DOT language: node statements, edge statements with chains such as
+// a -> b -> c, default attributes given with "edge [...]", and graph attributes, which are ignored. The weight of an
+// edge is its "weight" attribute, otherwise its "label" attribute, otherwise 1; attributes given on the edge come
+// before the defaults. Subgraphs, undirected graphs and HTML labels are not read. Errors are reported with a *DOTError.
+func ReadDOT(r io.Reader) (*Graph, error) {
+	document, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := tokenizeDOT(string(document))
+	if err != nil {
+		return nil, err
+	}
+	p := &dotParser{tokens: tokens}
+	if isDOTKeyword(p.peek(), "strict") {
+		p.next()
+	}
+	if token := p.next(); !isDOTKeyword(token, "digraph") {
+		if isDOTKeyword(token, "graph") {
+			return nil, p.errorf(token, "undirected graphs are not supported")
+		}
+		return nil, p.errorf(token, "expected \"digraph\", found %s", p.describe(token))
+	}
+	if !p.at("{") {
+		if _, err := p.id(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	graph := NewGraph()
+	addVertex := func(key string) {
+		if graph.findVertex(key) == nil {
+			graph.InsertVertex(key)
+		}
+	}
+	edgeDefaults := make(map[string]dotToken)
+	for !p.at("}") {
+		token := p.peek()
+		if token.eof {
+			return nil, p.errorf(token, "expected \"}\", found the end of the document")
+		}
+		if p.at(";") {
+			p.next()
+			continue
+		}
+		if p.at("{") || isDOTKeyword(token, "subgraph") {
+			return nil, p.errorf(token, "subgraphs are not supported")
+		}
+		if isDOTKeyword(token, "graph") || isDOTKeyword(token, "node") || isDOTKeyword(token, "edge") {
+			p.next()
+			attrs := make(map[string]dotToken)
+			if err := p.attributes(attrs); err != nil {
+				return nil, err
+			}
+			if isDOTKeyword(token, "edge") {
+				for name, value := range attrs {
+					edgeDefaults[name] = value
+				}
+			}
+			continue
+		}
+		from, err := p.nodeID()
+		if err != nil {
+			return nil, err
+		}
+		if p.at("=") { // a graph attribute
+			p.next()
+			if _, err := p.id(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if p.at("--") {
+			return nil, p.errorf(p.peek(), "undirected edges are not supported")
+		}
+		chain := []dotToken{from}
+		for p.at("->") {
+			p.next()
+			to, err := p.nodeID()
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, to)
+		}
+		attrs := make(map[string]dotToken)
+		if err := p.attributes(attrs); err != nil {
+			return nil, err
+		}
+		for _, node := range chain {
+			addVertex(node.text)
+		}
+		if len(chain) == 1 {
+			continue
+		}
+		weight, err := dotWeight(attrs, edgeDefaults)
+		if err != nil {
+			return nil, err
+		}
+		for i := 1; i < len(chain); i++ {
+			graph.InsertArc(chain[i-1].text, chain[i].text, weight)
+		}
+	}
+	p.next()
+	if token := p.next(); !token.eof {
+		return nil, p.errorf(token, "unexpected %s after the graph", p.describe(token))
+	}
+	return graph, nil
+}
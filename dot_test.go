@@ -0,0 +1,120 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	fmt.Println("Testing DOT export")
+	g := graph.NewGraph()
+	initGraph(g)
+	route, err := g.FindShortestRoute("a", "c")
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	var out strings.Builder
+	if err := g.WriteDOT(&out, graph.DOTOptions{Name: "towns", Route: route}); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+	}
+	dot := out.String()
+	for _, line := range []string{
+		`digraph "towns" {`,
+		`	"a" [color="red", penwidth=2];`,
+		`	"d";`,
+		`	"a" -> "b" [label="5", color="red", penwidth=2];`,
+		`	"b" -> "c" [label="4", color="red", penwidth=2];`,
+		`	"a" -> "d" [label="5"];`,
+	} {
+		if !strings.Contains(dot, line+"\n") {
+			t.Errorf("The DOT output should contain %s", line)
+		}
+	}
+
+	expected := graph.NewGraph()
+	initGraph(expected)
+	parsed, err := graph.ReadDOT(strings.NewReader(dot))
+	if err != nil || !reflect.DeepEqual(parsed, expected) {
+		t.Errorf("The DOT output should be read back into the same graph: %v", err)
+	}
+}
+
+func TestReadDOT(t *testing.T) {
+	fmt.Println("Testing DOT import")
+	document := `/* a small network */
+strict digraph "net" {
+	rankdir = LR;
+	node [shape=circle]
+	edge [weight=2]
+	"Central \"North\"" -> B -> C // two arcs weighing 2
+	B -> D [label="1.5"]; D -> B [weight=-1, label=x]
+	E
+}`
+	g, err := graph.ReadDOT(strings.NewReader(document))
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	distance, err := g.FindDistance([]string{`Central "North"`, "B", "D", "B", "C"})
+	if err != nil || distance != 4.5 || g.Count != 5 {
+		t.Errorf("The distance should be 4.5, not %v", distance)
+	}
+
+	for _, c := range []struct {
+		document     string
+		line, column int
+	}{
+		{"graph { a -- b }", 1, 1},
+		{"digraph {\n  a -> b [label=five]\n}", 2, 17},
+		{"digraph {\n  a -> \n}", 3, 1},
+		{"digraph { \"a }", 1, 11},
+	} {
+		_, err := graph.ReadDOT(strings.NewReader(c.document))
+		dotErr, ok := err.(*graph.DOTError)
+		if !ok || dotErr.Line != c.line || dotErr.Column != c.column {
+			t.Errorf("Error of %q should be at %d:%d, not %v", c.document, c.line, c.column, err)
+		}
+	}
+}
+
+func TestDOTRoundTrip(t *testing.T) {
+	fmt.Println("Testing DOT export and import of keys with backslashes")
+	g := graph.NewGraph()
+	keys := []string{`C:\dir`, `a\"b`, `"quoted"`, `\\x`}
+	for _, key := range keys {
+		g.InsertVertex(key)
+	}
+	g.InsertArc(`C:\dir`, `a\"b`, 2)
+	g.InsertArc(`a\"b`, `\\x`, 3)
+
+	var out strings.Builder
+	if err := g.WriteDOT(&out, graph.DOTOptions{}); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+	}
+	// only '"' is escaped, as Graphviz reads it
+	if !strings.Contains(out.String(), `"C:\dir" -> "a\\"b"`) || !strings.Contains(out.String(), `"\\x";`) {
+		t.Errorf("Only quotes should be escaped:\n%s", out.String())
+	}
+	read, err := graph.ReadDOT(strings.NewReader(out.String()))
+	if err != nil {
+		t.Errorf("Error should be NIL: %v\n%s", err, out.String())
+		return
+	}
+	if read.Count != len(keys) {
+		t.Errorf("There should be %d vertices, not %d", len(keys), read.Count)
+	}
+	distance, err := read.FindDistance([]string{`C:\dir`, `a\"b`, `\\x`})
+	if err != nil || distance != 5 {
+		t.Errorf("The distance should be 5, not %v (%v)", distance, err)
+	}
+
+	// a backslash ending a key would escape the closing quote
+	g.InsertVertex(`C:\`)
+	if err := g.WriteDOT(&out, graph.DOTOptions{}); err == nil {
+		t.Errorf("Error should NOT be NIL for a key ending with a backslash")
+	}
+}
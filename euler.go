@@ -0,0 +1,201 @@
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+// Check that the vertices with arcs are connected when the direction of the arcs is ignored.
+func (graph *Graph) arcsConnected() bool {
+	vertices, index := graph.indexVertices()
+	neighbours := undirectedNeighbours(vertices, index)
+	start := -1
+	for i, v := range vertices {
+		if v.InDegree+v.OutDegree > 0 {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return true
+	}
+	seen := reachable(neighbours, start, 0, false)
+	for i, v := range vertices {
+		if v.InDegree+v.OutDegree > 0 && !seen[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Check whether there is a closed walk using every arc exactly once. A graph without arcs has none, as
+// FindEulerianPath fails on it.
+func (graph *Graph) HasEulerianCircuit() bool {
+	arcs := false
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		if vPtr.InDegree != vPtr.OutDegree {
+			return false
+		}
+		arcs = arcs || vPtr.OutDegree > 0
+	}
+	return arcs && graph.arcsConnected()
+}
+
+// Check whether there is a walk, closed or not, using every arc exactly once.
+func (graph *Graph) HasEulerianPath() bool {
+	_, err := graph.eulerianStart()
+	return err == nil
+}
+
+// Return the vertex where an Eulerian path has to start, checking the degrees of the vertices.
+func (graph *Graph) eulerianStart() (*Vertex, error) {
+	var start, firstWithArcs *Vertex
+	ends := 0
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		if firstWithArcs == nil && vPtr.OutDegree > 0 {
+			firstWithArcs = vPtr
+		}
+		switch vPtr.OutDegree - vPtr.InDegree {
+		case 0:
+		case 1:
+			if start != nil {
+				return nil, errors.New("No Eulerian path: more than one vertex has an extra outgoing arc")
+			}
+			start = vPtr
+		case -1:
+			ends++
+			if ends > 1 {
+				return nil, errors.New("No Eulerian path: more than one vertex has an extra incoming arc")
+			}
+		default:
+			return nil, errors.New("No Eulerian path: vertex " + vPtr.Key + " is unbalanced")
+		}
+	}
+	if firstWithArcs == nil {
+		return nil, errors.New("Graph has no arcs")
+	}
+	if !graph.arcsConnected() {
+		return nil, errors.New("No Eulerian path: arcs are not connected")
+	}
+	if start == nil {
+		start = firstWithArcs
+	}
+	return start, nil
+}
+
+// Find a walk using every arc exactly once with Hierholzer's algorithm. When the graph has an Eulerian circuit,
+// the walk is closed and starts from the first vertex with arcs; otherwise it starts from the vertex with an
+// extra outgoing arc.
+func (graph *Graph) FindEulerianPath() ([]string, error) {
+	start, err := graph.eulerianStart()
+	if err != nil {
+		return nil, err
+	}
+	vertices, index := graph.indexVertices()
+	adjacent := make([][]int, len(vertices))
+	for i, v := range vertices {
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			adjacent[i] = append(adjacent[i], index[aPtr.Dest])
+		}
+	}
+	return keysOf(vertices, hierholzer(adjacent, index[start])), nil
+}
+
+// Return the walk using every arc of 'adjacent' once, starting from 'start'.
+// Arcs are used in the order of the adjacency lists.
+func hierholzer(adjacent [][]int, start int) []int {
+	used := make([]int, len(adjacent)) // number of arcs used from every vertex
+	stack := []int{start}
+	var walk []int
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		if used[v] < len(adjacent[v]) {
+			stack = append(stack, adjacent[v][used[v]])
+			used[v]++
+		} else {
+			walk = append(walk, v)
+			stack = stack[:len(stack)-1]
+		}
+	}
+	for i, j := 0, len(walk)-1; i < j; i, j = i+1, j-1 {
+		walk[i], walk[j] = walk[j], walk[i]
+	}
+	return walk
+}
+
+// Return the keys of the vertices at the given positions.
+func keysOf(vertices []*Vertex, positions []int) []string {
+	keys := make([]string, len(positions))
+	for i, p := range positions {
+		keys[i] = vertices[p].Key
+	}
+	return keys
+}
+
+// Find the cheapest closed walk from the vertex 'startKey' covering every arc at least once (the Chinese postman
+// problem). Unbalanced vertices are fixed by repeating the arcs chosen by a minimum-cost flow. The vertices with
+// arcs must be strongly connected. The result is the walk and its total weight.
+func (graph *Graph) FindChinesePostmanTour(startKey string) ([]string, float64, error) {
+	if graph.First == nil {
+		return nil, 0, errors.New("Graph is empty")
+	}
+	start := graph.findVertex(startKey)
+	if start == nil {
+		return nil, 0, errors.New("Key not found")
+	}
+	if start.OutDegree == 0 {
+		return nil, 0, errors.New("Start vertex has no arcs")
+	}
+	vertices, index := graph.indexVertices()
+	adjacent, _ := cheapestNeighbours(vertices, index)
+	forward := reachable(adjacent, index[start], 0, false)
+	backward := reachable(adjacent, index[start], 0, true)
+	for i, v := range vertices {
+		if v.InDegree+v.OutDegree > 0 && !(forward[i] && backward[i]) {
+			return nil, 0, errors.New("Vertices with arcs are not strongly connected")
+		}
+	}
+
+	// vertices with more incoming arcs must send extra walks to vertices with more outgoing arcs
+	n := len(vertices)
+	source, sink := n, n+1
+	net := newFlowNetwork(n + 2)
+	var ids []int
+	total := 0.0
+	for i, v := range vertices {
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			ids = append(ids, net.addArc(i, index[aPtr.Dest], math.Inf(1), aPtr.Weight))
+			total += aPtr.Weight
+		}
+	}
+	required := 0.0
+	for i, v := range vertices {
+		if surplus := v.InDegree - v.OutDegree; surplus > 0 {
+			net.addArc(source, i, float64(surplus), 0)
+			required += float64(surplus)
+		} else if surplus < 0 {
+			net.addArc(i, sink, float64(-surplus), 0)
+		}
+	}
+	routed, cost, err := net.minCostFlow(source, sink, required)
+	if err != nil {
+		return nil, 0, err
+	}
+	if routed < required {
+		return nil, 0, errors.New("No postman tour found")
+	}
+
+	// every arc is used once plus the number of times it is repeated
+	adjacent = make([][]int, n)
+	k := 0
+	for i, v := range vertices {
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			copies := 1 + int(math.Round(net.cap[ids[k]^1]))
+			for c := 0; c < copies; c++ {
+				adjacent[i] = append(adjacent[i], index[aPtr.Dest])
+			}
+			k++
+		}
+	}
+	return keysOf(vertices, hierholzer(adjacent, index[start])), total + cost, nil
+}
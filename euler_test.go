@@ -0,0 +1,84 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"testing"
+)
+
+func TestEulerianPath(t *testing.T) {
+	fmt.Println("Testing Eulerian paths and circuits")
+	g := graph.NewGraph()
+	initGraph(g)
+	if g.HasEulerianPath() || g.HasEulerianCircuit() {
+		t.Errorf("The graph should not have an Eulerian path")
+	}
+
+	g = graph.NewGraph()
+	for _, key := range []string{"a", "b", "c"} {
+		g.InsertVertex(key)
+	}
+	g.InsertArc("a", "b", 1)
+	g.InsertArc("b", "c", 1)
+	g.InsertArc("c", "a", 1)
+	g.InsertArc("a", "c", 1)
+	if !g.HasEulerianPath() || g.HasEulerianCircuit() {
+		t.Errorf("The graph should have an Eulerian path but no circuit")
+	}
+	result, err := g.FindEulerianPath()
+	fmt.Println(result)
+	if err != nil || !reflect.DeepEqual(result, []string{"a", "b", "c", "a", "c"}) {
+		t.Errorf("Solution is not correct")
+	}
+
+	g.InsertArc("c", "a", 1)
+	if !g.HasEulerianCircuit() {
+		t.Errorf("The graph should have an Eulerian circuit")
+	}
+	result, err = g.FindEulerianPath()
+	if err != nil || len(result) != 6 || result[0] != result[5] {
+		t.Errorf("The walk should be closed and use every arc")
+	}
+
+	// neither a path nor a circuit without arcs
+	g = graph.NewGraph()
+	g.InsertVertex("a")
+	_, err = g.FindEulerianPath()
+	if err == nil || g.HasEulerianPath() || g.HasEulerianCircuit() {
+		t.Errorf("A graph without arcs should have no Eulerian path or circuit")
+	}
+}
+
+func TestChinesePostmanTour(t *testing.T) {
+	fmt.Println("Testing Chinese postman tour")
+	g := graph.NewGraph()
+	initGraph(g)
+	if _, _, err := g.FindChinesePostmanTour("a"); err == nil {
+		t.Errorf("Error should NOT be NIL")
+	}
+
+	g = graph.NewGraph()
+	for _, key := range []string{"b", "c", "d", "e"} {
+		g.InsertVertex(key)
+	}
+	g.InsertArc("b", "c", 4)
+	g.InsertArc("c", "d", 8)
+	g.InsertArc("d", "c", 8)
+	g.InsertArc("d", "e", 6)
+	g.InsertArc("c", "e", 2)
+	g.InsertArc("e", "b", 3)
+	result, weight, err := g.FindChinesePostmanTour("b")
+	fmt.Println(result, weight)
+	if err != nil {
+		t.Errorf("Error should be NIL")
+	}
+	// the walk e-b-c-d is repeated
+	if weight != 46 || len(result) != 10 || result[0] != "b" || result[9] != "b" {
+		t.Errorf("Solution is not correct")
+	}
+	distance, err := g.FindDistance(result)
+	if err != nil || distance != weight {
+		t.Errorf("The weight should be the distance of the walk")
+	}
+}
@@ -0,0 +1,257 @@
+package graph
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type gexfDocument struct {
+	XMLName xml.Name  `xml:"gexf"`
+	Xmlns   string    `xml:"xmlns,attr,omitempty"`
+	Version string    `xml:"version,attr,omitempty"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Mode            string           `xml:"mode,attr,omitempty"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+	Other           []xmlElement     `xml:",any"`
+	OtherAttrs      []xml.Attr       `xml:",any,attr"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Mode       string          `xml:"mode,attr,omitempty"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID      string  `xml:"id,attr"`
+	Title   string  `xml:"title,attr"`
+	Type    string  `xml:"type,attr"`
+	Default *string `xml:"default"`
+}
+
+type gexfNode struct {
+	ID         string         `xml:"id,attr"`
+	Label      string         `xml:"label,attr,omitempty"`
+	AttValues  []gexfAttValue `xml:"attvalues>attvalue"`
+	Other      []xmlElement   `xml:",any"`
+	OtherAttrs []xml.Attr     `xml:",any,attr"`
+}
+
+type gexfEdge struct {
+	ID         string         `xml:"id,attr"`
+	Source     string         `xml:"source,attr"`
+	Target     string         `xml:"target,attr"`
+	Type       string         `xml:"type,attr,omitempty"`
+	Weight     string         `xml:"weight,attr,omitempty"`
+	Label      string         `xml:"label,attr,omitempty"`
+	AttValues  []gexfAttValue `xml:"attvalues>attvalue"`
+	Other      []xmlElement   `xml:",any"`
+	OtherAttrs []xml.Attr     `xml:",any,attr"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// GEXF names of the kinds of attribute values.
+var gexfTypes = map[string]string{
+	attributeString: "string", attributeInt: "long", attributeFloat: "double", attributeBool: "boolean",
+}
+
+// Write the graph as a directed GEXF 1.3 document. Vertices are nodes labelled with their keys and arcs are edges
+// with their weights. Vertex and arc attributes are declared as GEXF attributes of the types string, long, double
+// and boolean; an attribute must have the same kind everywhere.
+func (graph *Graph) WriteGEXF(w io.Writer) error {
+	document := gexfDocument{Xmlns: "http://gexf.net/1.3", Version: "1.3", Graph: gexfGraph{DefaultEdgeType: "directed", Mode: "static"}}
+	names := make([][]string, 2) // names of the node and of the edge attributes; their ids are their positions
+	for i, class := range []string{"node", "edge"} {
+		var kinds map[string]string
+		var err error
+		if names[i], kinds, err = graph.attributeKinds(class == "edge"); err != nil {
+			return err
+		}
+		if len(names[i]) == 0 {
+			continue
+		}
+		declaration := gexfAttributes{Class: class}
+		for id, name := range names[i] {
+			declaration.Attributes = append(declaration.Attributes, gexfAttribute{ID: strconv.Itoa(id), Title: name, Type: gexfTypes[kinds[name]]})
+		}
+		document.Graph.Attributes = append(document.Graph.Attributes, declaration)
+	}
+	values := func(names []string, attributes Attributes) []gexfAttValue {
+		var result []gexfAttValue
+		for id, name := range names {
+			if value, ok := attributes[name]; ok {
+				result = append(result, gexfAttValue{For: strconv.Itoa(id), Value: formatAttribute(value)})
+			}
+		}
+		return result
+	}
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		document.Graph.Nodes = append(document.Graph.Nodes, gexfNode{ID: vPtr.Key, Label: vPtr.Key, AttValues: values(names[0], vPtr.Attributes)})
+	}
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		for _, aPtr := range vPtr.arcsInInsertionOrder() {
+			if math.IsInf(aPtr.Weight, 0) || math.IsNaN(aPtr.Weight) {
+				return errors.New("Weight of the arc " + vPtr.Key + "-" + aPtr.Dest.Key + " is not finite")
+			}
+			document.Graph.Edges = append(document.Graph.Edges, gexfEdge{ID: strconv.Itoa(len(document.Graph.Edges)),
+				Source: vPtr.Key, Target: aPtr.Dest.Key, Weight: formatAttribute(aPtr.Weight), AttValues: values(names[1], aPtr.Attributes)})
+		}
+	}
+	return writeXML(w, document)
+}
+
+// Read a GEXF document. Nodes become vertices keyed by their ids and edges become arcs weighted by their weight,
+// 1 when it is missing; undirected and mutual edges become arcs in both directions, and edges are undirected unless
+// the graph or the edge says otherwise, as in the GEXF specification. Attribute values become attributes typed
+// after their declarations: integer and long as int64, float and double as float64, boolean as bool and string as
+// string. Edge labels and node labels that differ from the id are kept as the attribute "label". Attributes of
+// other types, dynamic data and visualization data are returned as unknown attributes.
+func ReadGEXF(r io.Reader) (*Graph, []UnknownAttribute, error) {
+	var document gexfDocument
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, nil, errors.New("GEXF: " + err.Error())
+	}
+	g := document.Graph
+	unknown := &unknownAttributes{}
+	unknown.addXML("graph", g.OtherAttrs, g.Other)
+
+	type attribute struct {
+		name, kind string
+		fallback   interface{}
+	}
+	declared := map[string]map[string]attribute{"node": {}, "edge": {}}
+	for _, declaration := range g.Attributes {
+		if declared[declaration.Class] == nil {
+			return nil, nil, errors.New("GEXF: unknown attribute class " + declaration.Class)
+		}
+		if declaration.Mode == "dynamic" {
+			for _, a := range declaration.Attributes {
+				unknown.add(declaration.Class, a.Title, "dynamic attribute")
+				declared[declaration.Class][a.ID] = attribute{name: a.Title}
+			}
+			continue
+		}
+		for _, a := range declaration.Attributes {
+			kind := ""
+			switch a.Type {
+			case "string":
+				kind = attributeString
+			case "integer", "long", "short", "byte":
+				kind = attributeInt
+			case "float", "double":
+				kind = attributeFloat
+			case "boolean":
+				kind = attributeBool
+			default:
+				unknown.add(declaration.Class, a.Title, "unsupported type "+a.Type)
+				declared[declaration.Class][a.ID] = attribute{name: a.Title}
+				continue
+			}
+			var fallback interface{}
+			if a.Default != nil {
+				value, err := parseAttribute(kind, *a.Default)
+				if err != nil {
+					return nil, nil, errors.New("GEXF: invalid default of the attribute " + a.Title + ": " + err.Error())
+				}
+				fallback = value
+			}
+			declared[declaration.Class][a.ID] = attribute{name: a.Title, kind: kind, fallback: fallback}
+		}
+	}
+	attributes := func(class string, attValues []gexfAttValue) (Attributes, error) {
+		var result Attributes
+		set := func(name string, value interface{}) {
+			if result == nil {
+				result = make(Attributes)
+			}
+			result[name] = value
+		}
+		for _, a := range declared[class] {
+			if a.fallback != nil {
+				set(a.name, a.fallback)
+			}
+		}
+		for _, v := range attValues {
+			a, ok := declared[class][v.For]
+			if !ok {
+				unknown.add(class, v.For, "undeclared attribute")
+				continue
+			}
+			if a.kind == "" {
+				continue // already reported
+			}
+			value, err := parseAttribute(a.kind, v.Value)
+			if err != nil {
+				return nil, errors.New("GEXF: invalid " + a.name + " of a " + class + ": " + err.Error())
+			}
+			set(a.name, value)
+		}
+		return result, nil
+	}
+
+	builder := newGraphBuilder()
+	for _, node := range g.Nodes {
+		if node.ID == "" {
+			return nil, nil, errors.New("GEXF: node without id")
+		}
+		if _, ok := builder.vertices[node.ID]; ok {
+			return nil, nil, errors.New("GEXF: duplicate node " + node.ID)
+		}
+		unknown.addXML("node", node.OtherAttrs, node.Other)
+		attrs, err := attributes("node", node.AttValues)
+		if err != nil {
+			return nil, nil, err
+		}
+		if node.Label != "" && node.Label != node.ID {
+			if attrs == nil {
+				attrs = make(Attributes)
+			}
+			attrs["label"] = node.Label
+		}
+		builder.vertex(node.ID).Attributes = attrs
+	}
+	for _, edge := range g.Edges {
+		from, to := builder.vertices[edge.Source], builder.vertices[edge.Target]
+		if from == nil || to == nil {
+			return nil, nil, errors.New("GEXF: edge " + edge.Source + "-" + edge.Target + " between unknown nodes")
+		}
+		unknown.addXML("edge", edge.OtherAttrs, edge.Other)
+		weight := 1.0
+		if edge.Weight != "" {
+			var err error
+			if weight, err = strconv.ParseFloat(strings.TrimSpace(edge.Weight), 64); err != nil {
+				return nil, nil, errors.New("GEXF: invalid weight of the edge " + edge.Source + "-" + edge.Target)
+			}
+		}
+		attrs, err := attributes("edge", edge.AttValues)
+		if err != nil {
+			return nil, nil, err
+		}
+		if edge.Label != "" {
+			if attrs == nil {
+				attrs = make(Attributes)
+			}
+			attrs["label"] = edge.Label
+		}
+		edgeType := edge.Type
+		if edgeType == "" {
+			edgeType = g.DefaultEdgeType
+		}
+		insertEdge(from, to, weight, attrs, edgeType == "directed")
+	}
+	return builder.graph, unknown.list, nil
+}
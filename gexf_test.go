@@ -0,0 +1,68 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGEXFRoundTrip(t *testing.T) {
+	fmt.Println("Testing GEXF round trips")
+	g := attributedGraph()
+	var out strings.Builder
+	if err := g.WriteGEXF(&out); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	parsed, unknown, err := graph.ReadGEXF(strings.NewReader(out.String()))
+	if err != nil || len(unknown) != 0 || !reflect.DeepEqual(parsed, g) {
+		t.Errorf("The GEXF should be read back into the same graph: %v %v\n%s", err, unknown, out.String())
+	}
+}
+
+func TestReadGEXF(t *testing.T) {
+	fmt.Println("Testing GEXF import")
+	document := `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" xmlns:viz="http://gexf.net/1.3/viz" version="1.3">
+  <graph mode="static">
+    <attributes class="node">
+      <attribute id="0" title="capacity" type="float"><default>1.5</default></attribute>
+      <attribute id="1" title="tags" type="liststring"/>
+    </attributes>
+    <nodes>
+      <node id="n1" label="North"><attvalues><attvalue for="0" value="4"/><attvalue for="1" value="[a]"/></attvalues>
+        <viz:color r="1" g="2" b="3"/></node>
+      <node id="n2"/>
+    </nodes>
+    <edges>
+      <edge id="0" source="n1" target="n2" weight="2.5"/>
+      <edge id="1" source="n2" target="n2" type="directed" label="loop"/>
+    </edges>
+  </graph>
+</gexf>`
+	g, unknown, err := graph.ReadGEXF(strings.NewReader(document))
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	distance, err := g.FindDistance([]string{"n2", "n1", "n2", "n2"})
+	if err != nil || distance != 6 {
+		t.Errorf("Edges should be undirected by default: %v %v", distance, err)
+	}
+	expected := graph.Attributes{"capacity": 4.0, "label": "North"}
+	if !reflect.DeepEqual(g.First.Attributes, expected) || g.First.NextVertex.Attributes["capacity"] != 1.5 {
+		t.Errorf("Attributes should be %v, not %v", expected, g.First.Attributes)
+	}
+	if g.First.NextVertex.Arc.NextArc.Attributes["label"] != "loop" {
+		t.Errorf("The edge label should be kept")
+	}
+	expectedUnknown := []graph.UnknownAttribute{
+		{Element: "node", Name: "tags", Reason: "unsupported type liststring"},
+		{Element: "node", Name: "color", Reason: "unknown XML element"},
+	}
+	if !reflect.DeepEqual(unknown, expectedUnknown) {
+		t.Errorf("Unknown attributes should be %v, not %v", expectedUnknown, unknown)
+	}
+}
@@ -16,13 +16,31 @@ type Vertex struct {
 	Parent     *Vertex // Used when the solution of the given problem is a path, not a state. The value is used to trace to the path.
 	InTree     bool
 	PathLength float64
+
+	Attributes Attributes // data about the vertex kept by the encodings, e.g. a name or a position
 }
 
 type Arc struct {
-	Dest    *Vertex
-	NextArc *Arc
+	Dest        *Vertex
+	NextArc     *Arc
+	Weight      float64
+	InTree      bool
+	Capacity    float64   // used by flow problems when HasCapacity is set
+	HasCapacity bool      // set by InsertArcWithCapacity. Arcs inserted with InsertArc have no capacity.
+	Weights     []float64 // several weights of the arc, e.g. distance and fare, for multi-criteria searches
+
+	TravelTime TravelTime // travel time depending on the departure time. When nil, the travel time is the Weight.
+	Attributes Attributes // data about the arc kept by the encodings, e.g. a line name
+}
+
+// Attributes are named values attached to a vertex or an arc. Values are strings, int64, float64 or bool.
+type Attributes map[string]interface{}
+
+// Edge describes an arc by the keys of its end vertices. It is used to report arcs in results.
+type Edge struct {
+	FromKey string
+	ToKey   string
 	Weight  float64
-	InTree  bool
 }
 
 func NewVertex() *Vertex {
@@ -88,21 +106,132 @@ func (graph *Graph) DeleteVertex(dataKey string) {
 	}
 }
 
+// Delete an arc from the vertex 'fromKey' to the vertex 'toKey'. Of parallel arcs, the one inserted last is
+// deleted, so deleting an arc undoes inserting it.
+func (graph *Graph) DeleteArc(fromKey, toKey string) error {
+	fromPtr := graph.findVertex(fromKey)
+	if fromPtr == nil {
+		return errors.New("FromKey not found")
+	}
+	var prePtr *Arc = nil
+	locPtr := fromPtr.Arc
+	for locPtr != nil && toKey > locPtr.Dest.Key {
+		prePtr = locPtr
+		locPtr = locPtr.NextArc
+	}
+	if locPtr == nil || locPtr.Dest.Key != toKey {
+		return errors.New("Arc not found")
+	}
+	if prePtr == nil {
+		fromPtr.Arc = locPtr.NextArc
+	} else {
+		prePtr.NextArc = locPtr.NextArc
+	}
+	fromPtr.OutDegree--
+	locPtr.Dest.InDegree--
+	return nil
+}
+
+// Return the vertex with the key 'dataKey', or nil when there is no such vertex.
+func (graph *Graph) findVertex(dataKey string) *Vertex {
+	ptr := graph.First
+	for ptr != nil && dataKey > ptr.Key {
+		ptr = ptr.NextVertex
+	}
+	if ptr == nil || ptr.Key != dataKey {
+		return nil
+	}
+	return ptr
+}
+
+// Return the vertices in the order of the vertex list together with their positions in that list.
+func (graph *Graph) indexVertices() ([]*Vertex, map[*Vertex]int) {
+	vertices := make([]*Vertex, 0, graph.Count)
+	index := make(map[*Vertex]int, graph.Count)
+	for ptr := graph.First; ptr != nil; ptr = ptr.NextVertex {
+		index[ptr] = len(vertices)
+		vertices = append(vertices, ptr)
+	}
+	return vertices, index
+}
+
+// graphBuilder builds a graph from vertex keys, e.g. for the decoders. Vertices are found through a map, and a vertex
+// whose key comes after every other key is appended without walking the vertex list, so graphs written in key order
+// are read fast.
+type graphBuilder struct {
+	graph    *Graph
+	vertices map[string]*Vertex
+	last     *Vertex
+}
+
+func newGraphBuilder() *graphBuilder {
+	return &graphBuilder{graph: NewGraph(), vertices: make(map[string]*Vertex)}
+}
+
+// Return the vertex with the key 'key', inserting it when it does not exist yet.
+func (b *graphBuilder) vertex(key string) *Vertex {
+	if vPtr, ok := b.vertices[key]; ok {
+		return vPtr
+	}
+	if b.last == nil || key > b.last.Key {
+		vPtr := NewVertex()
+		vPtr.Key = key
+		if b.last == nil {
+			b.graph.First = vPtr
+		} else {
+			b.last.NextVertex = vPtr
+		}
+		b.graph.Count++
+		b.last = vPtr
+		b.vertices[key] = vPtr
+		return vPtr
+	}
+	b.graph.InsertVertex(key)
+	b.vertices[key] = b.graph.findVertex(key)
+	return b.vertices[key]
+}
+
+// Return the arcs of a vertex in the order in which inserting them again rebuilds the same arc list. A new arc is
+// inserted before the arcs to the same vertex, so parallel arcs come in reverse order.
+func (vPtr *Vertex) arcsInInsertionOrder() []*Arc {
+	var arcs, group []*Arc
+	for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+		group = append([]*Arc{aPtr}, group...)
+		if aPtr.NextArc == nil || aPtr.NextArc.Dest != aPtr.Dest {
+			arcs = append(arcs, group...)
+			group = nil
+		}
+	}
+	return arcs
+}
+
 func (graph *Graph) InsertArc(fromKey, toKey string, weight float64) error {
+	_, err := graph.insertArc(fromKey, toKey, weight)
+	return err
+}
+
+// Insert an arc and return it.
+func (graph *Graph) insertArc(fromKey, toKey string, weight float64) (*Arc, error) {
 	var fromPtr *Vertex = graph.First
 	for fromPtr != nil && fromKey > fromPtr.Key {
 		fromPtr = fromPtr.NextVertex
 	}
 	if fromPtr == nil || fromKey != fromPtr.Key {
-		return errors.New("FromKey not found")
+		return nil, errors.New("FromKey not found")
 	}
 	var toPtr *Vertex = graph.First
 	for toPtr != nil && toKey > toPtr.Key {
 		toPtr = toPtr.NextVertex
 	}
 	if toPtr == nil || toKey != toPtr.Key {
-		return errors.New("ToKey not found")
+		return nil, errors.New("ToKey not found")
 	}
+	return linkArc(fromPtr, toPtr, weight), nil
+}
+
+// Insert an arc between two vertices of the graph and return it.
+func linkArc(fromPtr, toPtr *Vertex, weight float64) *Arc {
+	toKey := toPtr.Key
 	var newArc *Arc = NewArc(weight)
 	newArc.Dest = toPtr
 	fromPtr.OutDegree++
@@ -124,6 +253,29 @@ func (graph *Graph) InsertArc(fromKey, toKey string, weight float64) error {
 		}
 		newArc.NextArc = arcWalkPtr
 	}
+	return newArc
+}
+
+// Insert an arc with a capacity 'capacity'. The weight of the arc is its cost per unit of flow.
+func (graph *Graph) InsertArcWithCapacity(fromKey, toKey string, capacity, cost float64) error {
+	aPtr, err := graph.insertArc(fromKey, toKey, cost)
+	if err != nil {
+		return err
+	}
+	aPtr.Capacity, aPtr.HasCapacity = capacity, true
+	return nil
+}
+
+// Insert an arc with several weights 'weights'. The weight of the arc is the first of them.
+func (graph *Graph) InsertArcWithWeights(fromKey, toKey string, weights []float64) error {
+	if len(weights) == 0 {
+		return errors.New("No weights given")
+	}
+	aPtr, err := graph.insertArc(fromKey, toKey, weights[0])
+	if err != nil {
+		return err
+	}
+	aPtr.Weights = append([]float64(nil), weights...)
 	return nil
 }
 
@@ -141,6 +293,7 @@ type Queue struct {
 	Rear            *QueueNode
 	Count           int
 	IsPriorityQueue bool
+	Better          func(a, b *Vertex) bool // order of a priority queue. When nil, a lower PathLength comes first.
 }
 
 func NewQueue(isPriorityQueue bool) *Queue {
@@ -166,7 +319,7 @@ func (queue *Queue) Enqueue(data *Vertex) {
 			// search to find a correct place to put newPtr
 			var prePtr *QueueNode
 			prePtr = nil
-			for ptr != nil && data.PathLength > ptr.Data.PathLength {
+			for ptr != nil && queue.before(ptr.Data, data) {
 				prePtr = ptr
 				ptr = ptr.Next
 			}
@@ -184,6 +337,14 @@ func (queue *Queue) Enqueue(data *Vertex) {
 	queue.Count++
 }
 
+// Check whether 'a' must stay before 'b' in a priority queue.
+func (queue *Queue) before(a, b *Vertex) bool {
+	if queue.Better != nil {
+		return queue.Better(a, b)
+	}
+	return b.PathLength > a.PathLength
+}
+
 func (queue *Queue) Dequeue(dataOut **Vertex) error {
 	if queue.Count == 0 {
 		return errors.New("Queue is empty")
@@ -431,68 +592,137 @@ func (graph *Graph) FindTripExactStops(fromKey, toKey string, stops int) ([]stri
 	return nil, errors.New("No solution found")
 }
 
+// Find every trip from the vertex 'fromKey' to the vertex 'toKey' with at least 'minStops' and at most 'maxStops'
+// stops. Trips may pass through the same vertices several times; they are returned by number of stops, and trips
+// using different parallel arcs are returned once for each arc.
+func (graph *Graph) FindTrips(fromKey, toKey string, minStops, maxStops int) ([][]string, error) {
+	if graph.First == nil {
+		return nil, errors.New("Graph is empty")
+	}
+	from := graph.findVertex(fromKey)
+	if from == nil {
+		return nil, errors.New("FromKey not found")
+	}
+	if graph.findVertex(toKey) == nil {
+		return nil, errors.New("ToKey not found")
+	}
+	// breadth-first search over the trips, one level per stop
+	var solutions [][]string
+	level := [][]*Vertex{{from}}
+	for stops := 1; stops <= maxStops && len(level) > 0; stops++ {
+		var next [][]*Vertex
+		for _, trip := range level {
+			for aPtr := trip[len(trip)-1].Arc; aPtr != nil; aPtr = aPtr.NextArc {
+				extended := append(trip[:len(trip):len(trip)], aPtr.Dest)
+				if stops >= minStops && aPtr.Dest.Key == toKey {
+					keys := make([]string, len(extended))
+					for i, vPtr := range extended {
+						keys[i] = vPtr.Key
+					}
+					solutions = append(solutions, keys)
+				}
+				next = append(next, extended)
+			}
+		}
+		level = next
+	}
+	return solutions, nil
+}
+
 // This method uses Best-First-Search algorithm with the help of a priority queue.
 func (graph *Graph) FindShortestRoute(fromKey string, toKey string) ([]string, error) {
+	route, _, err := graph.FindBestRoute(fromKey, toKey, ShortestPath{})
+	return route, err
+}
+
+// Find the best route from the vertex 'fromKey' to the vertex 'toKey' under the path algebra 'semiring' with a
+// uniform-cost search. The PathLength of a vertex is the value of the best path found so far.
+// The semiring must be selective (Combine returns one of its arguments) and extending a path must never make
+// it better, as for ShortestPath, WidestPath and ReliablePath. The result is the route and its value.
+func (graph *Graph) FindBestRoute(fromKey, toKey string, semiring Semiring) ([]string, float64, error) {
 	// Find vertex with the key
 	if graph.First == nil {
-		return nil, errors.New("Graph is empty")
+		return nil, 0, errors.New("Graph is empty")
 	}
 	vFromPtr := graph.First
 	for vFromPtr != nil && vFromPtr.Key != fromKey {
 		vFromPtr = vFromPtr.NextVertex
 	}
 	if vFromPtr == nil {
-		return nil, errors.New("FromKey not found")
+		return nil, 0, errors.New("FromKey not found")
 	}
 	vToPtr := graph.First
 	for vToPtr != nil && vToPtr.Key != toKey {
 		vToPtr = vToPtr.NextVertex
 	}
 	if vToPtr == nil {
-		return nil, errors.New("ToKey not found")
+		return nil, 0, errors.New("ToKey not found")
 	}
 
+	if vPtr := graph.search(vFromPtr, vToPtr, semiring, nil); vPtr != nil {
+		return processSolution(vPtr), vPtr.PathLength, nil
+	}
+	return nil, 0, errors.New("No solution found")
+}
+
+// Run a uniform-cost search from the vertex 'source' under the path algebra 'semiring' until the vertex 'target' is
+// dequeued, or over every reachable vertex when 'target' is nil. The weight of an arc is given by 'arcWeight',
+// which also tells whether the arc may be used; a nil 'arcWeight' uses every arc with its Weight.
+// PathLength and Parent of every vertex are updated; unreached vertices keep semiring.Zero().
+// The result is the target when it is reached, nil otherwise.
+func (graph *Graph) search(source, target *Vertex, semiring Semiring, arcWeight func(from *Vertex, arc *Arc) (float64, bool)) *Vertex {
 	vPtr := graph.First
 	for vPtr != nil {
-		vPtr.PathLength = math.MaxFloat64 // PathLength keeps distance from the source to the current vertex. It is initialised to INFINITY
+		vPtr.PathLength = semiring.Zero() // PathLength keeps the value of the path from the source. It is initialised to "no path"
 		vPtr.Processed = false
+		vPtr.Parent = nil
 		vPtr = vPtr.NextVertex
 	}
+	source.PathLength = semiring.One() // value of the empty path from source to source
 
-	// use Uniform-cost search to traverse a graph
-	vPtr = vFromPtr
-	vPtr.PathLength = 0.0 // distance from source to source is 0
-
-	vPtr.Parent = nil
 	queue := NewQueue(true)
-	queue.Enqueue(vPtr)
-	vPtr.Processed = true // true means that it is used to be in the queue
+	queue.Better = func(a, b *Vertex) bool {
+		return better(semiring, a.PathLength, b.PathLength)
+	}
+	queue.Enqueue(source)
+	source.Processed = true // true means that it is used to be in the queue
 	for !queue.IsEmpty() {
 		queue.Dequeue(&vPtr)
-		//fmt.Println("Dequeue:", vPtr.Key)
-		if vPtr.Key == toKey {
-			return processSolution(vPtr), nil
+		if vPtr == target {
+			return vPtr
 		}
-		aPtr := vPtr.Arc
-		for aPtr != nil {
+		for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			weight := aPtr.Weight
+			if arcWeight != nil {
+				var ok bool
+				if weight, ok = arcWeight(vPtr, aPtr); !ok {
+					continue
+				}
+			}
 			dest := aPtr.Dest
-			if !dest.Processed {
-				dest.PathLength = vPtr.PathLength + aPtr.Weight
+			pathLength := semiring.Extend(vPtr.PathLength, weight)
+			if !dest.Processed || better(semiring, pathLength, dest.PathLength) {
+				dest.PathLength = pathLength
 				dest.Parent = vPtr // update parent to trace the solution
 				queue.Enqueue(dest)
 				dest.Processed = true
-			} else {
-				if dest.PathLength > vPtr.PathLength+aPtr.Weight {
-					dest.PathLength = vPtr.PathLength + aPtr.Weight
-					dest.Parent = vPtr
-					queue.Enqueue(dest)
-					dest.Processed = true
-				}
 			}
-			aPtr = aPtr.NextArc
 		}
 	}
-	return nil, errors.New("No solution found")
+	return nil
+}
+
+// Compute the shortest distances from the vertex 'source' to every vertex. Only the arcs accepted by 'allow' are
+// used; a nil 'allow' accepts every arc. PathLength and Parent of every vertex are updated; unreachable vertices
+// keep INFINITY.
+func (graph *Graph) findShortestDistances(source *Vertex, allow func(from *Vertex, arc *Arc) bool) {
+	var arcWeight func(from *Vertex, arc *Arc) (float64, bool)
+	if allow != nil {
+		arcWeight = func(from *Vertex, arc *Arc) (float64, bool) {
+			return arc.Weight, allow(from, arc)
+		}
+	}
+	graph.search(source, nil, ShortestPath{}, arcWeight)
 }
 
 // This method uses Best-First-Search algorithm with the help of a priority queue.
@@ -203,6 +203,54 @@ func TestProblem10(t *testing.T) {
 	}
 }
 
+func TestFindTrips(t *testing.T) {
+	fmt.Println("Testing all trips with a range of stops")
+	graph := graph.NewGraph()
+	initGraph(graph)
+	result, err := graph.FindTrips("c", "c", 1, 3)
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+	}
+	expected := [][]string{{"c", "d", "c"}, {"c", "e", "b", "c"}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	result, _ = graph.FindTrips("a", "c", 4, 4)
+	expected = [][]string{{"a", "b", "c", "d", "c"}, {"a", "d", "c", "d", "c"}, {"a", "d", "e", "b", "c"}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if _, err := graph.FindTrips("a", "x", 1, 3); err == nil {
+		t.Errorf("An unknown vertex should be an error")
+	}
+}
+
+func TestDeleteArc(t *testing.T) {
+	fmt.Println("Testing arc deletion")
+	graph := graph.NewGraph()
+	initGraph(graph)
+	graph.InsertArc("a", "b", 1)
+	if err := graph.DeleteArc("a", "b"); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+	}
+	if distance, _ := graph.FindDistance([]string{"a", "b"}); distance != 5 {
+		t.Errorf("The last parallel arc should be deleted, got the distance %v", distance)
+	}
+	graph.DeleteArc("a", "b")
+	if _, err := graph.FindDistance([]string{"a", "b"}); err == nil {
+		t.Errorf("The arc a-b should be deleted")
+	}
+	if err := graph.DeleteArc("a", "b"); err == nil {
+		t.Errorf("Deleting a missing arc should be an error")
+	}
+	graph.DeleteArc("e", "b")
+	graph.DeleteArc("b", "c")
+	graph.DeleteVertex("b")
+	if graph.Count != 4 {
+		t.Errorf("The vertex b should be deleted once it has no arcs")
+	}
+}
+
 func TestPriorityQueue(t *testing.T) {
 	fmt.Println("Testing priority queue")
 	queue := graph.NewQueue(true)
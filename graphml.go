@@ -0,0 +1,452 @@
+package graph
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UnknownAttribute is a piece of data of a GraphML or GEXF document that the reader could not map to the graph,
+// e.g. an attribute of an unsupported type or drawing information. Readers return them instead of dropping them
+// silently.
+type UnknownAttribute struct {
+	Element string // "graph", "node" or "edge"
+	Name    string
+	Reason  string
+}
+
+// Kinds of attribute values.
+const (
+	attributeString = "string"
+	attributeInt    = "int64"
+	attributeFloat  = "float64"
+	attributeBool   = "bool"
+)
+
+// Return the kind of an attribute value, or "" when the value cannot be written.
+func attributeKind(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return attributeString
+	case int64:
+		return attributeInt
+	case float64:
+		return attributeFloat
+	case bool:
+		return attributeBool
+	}
+	return ""
+}
+
+// Return the names of the attributes of the vertices, or of the arcs when 'arcs' is true, in order, with their
+// kinds. An attribute must have the same kind everywhere.
+func (graph *Graph) attributeKinds(arcs bool) ([]string, map[string]string, error) {
+	kinds := make(map[string]string)
+	check := func(attributes Attributes) error {
+		for name, value := range attributes {
+			kind := attributeKind(value)
+			if kind == "" {
+				return errors.New("Attribute " + name + " is not a string, int64, float64 or bool")
+			}
+			if old, ok := kinds[name]; ok && old != kind {
+				return errors.New("Attribute " + name + " has values of the kinds " + old + " and " + kind)
+			}
+			kinds[name] = kind
+		}
+		return nil
+	}
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		if !arcs {
+			if err := check(vPtr.Attributes); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			if err := check(aPtr.Attributes); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	names := make([]string, 0, len(kinds))
+	for name := range kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, kinds, nil
+}
+
+// Write an attribute value as text.
+func formatAttribute(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// Read an attribute value of the kind 'kind' from text.
+func parseAttribute(kind, text string) (interface{}, error) {
+	text = strings.TrimSpace(text)
+	switch kind {
+	case attributeInt:
+		return strconv.ParseInt(text, 10, 64)
+	case attributeFloat:
+		return strconv.ParseFloat(text, 64)
+	case attributeBool:
+		return strconv.ParseBool(text)
+	}
+	return text, nil
+}
+
+// Collects the unknown attributes of a document, each once.
+type unknownAttributes struct {
+	list []UnknownAttribute
+	seen map[UnknownAttribute]bool
+}
+
+func (u *unknownAttributes) add(element, name, reason string) {
+	attribute := UnknownAttribute{Element: element, Name: name, Reason: reason}
+	if u.seen == nil {
+		u.seen = make(map[UnknownAttribute]bool)
+	}
+	if !u.seen[attribute] {
+		u.seen[attribute] = true
+		u.list = append(u.list, attribute)
+	}
+}
+
+// Report the XML attributes and child elements of an element that the reader does not know.
+func (u *unknownAttributes) addXML(element string, attrs []xml.Attr, children []xmlElement) {
+	for _, attr := range attrs {
+		if attr.Name.Space != "xmlns" && attr.Name.Local != "xmlns" {
+			u.add(element, attr.Name.Local, "unknown XML attribute")
+		}
+	}
+	for _, child := range children {
+		u.add(element, child.XMLName.Local, "unknown XML element")
+	}
+}
+
+// An XML element that is not read.
+type xmlElement struct {
+	XMLName xml.Name
+}
+
+// Insert the arcs of an edge: one arc when the edge is directed, arcs both ways otherwise.
+func insertEdge(from, to *Vertex, weight float64, attributes Attributes, directed bool) {
+	aPtr := linkArc(from, to, weight)
+	aPtr.Attributes = attributes
+	if !directed && from != to {
+		aPtr = linkArc(to, from, weight)
+		if attributes != nil {
+			aPtr.Attributes = make(Attributes, len(attributes))
+			for name, value := range attributes {
+				aPtr.Attributes[name] = value
+			}
+		}
+	}
+}
+
+type graphMLDocument struct {
+	XMLName xml.Name       `xml:"graphml"`
+	Xmlns   string         `xml:"xmlns,attr,omitempty"`
+	Keys    []graphMLKey   `xml:"key"`
+	Graphs  []graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID         string  `xml:"id,attr"`
+	For        string  `xml:"for,attr"`
+	Name       string  `xml:"attr.name,attr,omitempty"`
+	Type       string  `xml:"attr.type,attr,omitempty"`
+	YFilesType string  `xml:"yfiles.type,attr,omitempty"`
+	Default    *string `xml:"default"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Data        []graphMLData `xml:"data"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+	Hyperedges  []xmlElement  `xml:"hyperedge"`
+	Other       []xmlElement  `xml:",any"`
+	OtherAttrs  []xml.Attr    `xml:",any,attr"`
+}
+
+type graphMLNode struct {
+	ID         string         `xml:"id,attr"`
+	Data       []graphMLData  `xml:"data"`
+	Graphs     []graphMLGraph `xml:"graph"`
+	Other      []xmlElement   `xml:",any"`
+	OtherAttrs []xml.Attr     `xml:",any,attr"`
+}
+
+type graphMLEdge struct {
+	ID         string        `xml:"id,attr,omitempty"`
+	Source     string        `xml:"source,attr"`
+	Target     string        `xml:"target,attr"`
+	Directed   string        `xml:"directed,attr,omitempty"`
+	Data       []graphMLData `xml:"data"`
+	Other      []xmlElement  `xml:",any"`
+	OtherAttrs []xml.Attr    `xml:",any,attr"`
+}
+
+type graphMLData struct {
+	Key      string       `xml:"key,attr"`
+	Value    string       `xml:",chardata"`
+	Children []xmlElement `xml:",any"`
+}
+
+// GraphML names of the kinds of attribute values.
+var graphMLTypes = map[string]string{
+	attributeString: "string", attributeInt: "long", attributeFloat: "double", attributeBool: "boolean",
+}
+
+// Write the graph as a directed GraphML document. Vertices are nodes and arcs are edges with their weight in the
+// edge attribute "weight". Vertex and arc attributes are declared as GraphML keys of the types string, long, double
+// and boolean. An arc attribute may not be called "weight", and an attribute must have the same kind everywhere.
+func (graph *Graph) WriteGraphML(w io.Writer) error {
+	vertexNames, vertexKinds, err := graph.attributeKinds(false)
+	if err != nil {
+		return err
+	}
+	arcNames, arcKinds, err := graph.attributeKinds(true)
+	if err != nil {
+		return err
+	}
+	if _, ok := arcKinds["weight"]; ok {
+		return errors.New("Arc attribute weight clashes with the weight of the arcs")
+	}
+	document := graphMLDocument{Xmlns: "http://graphml.graphdrawing.org/xmlns"}
+	document.Keys = append(document.Keys, graphMLKey{ID: "weight", For: "edge", Name: "weight", Type: "double"})
+	vertexKeys, arcKeys := make(map[string]string), make(map[string]string)
+	for i, name := range vertexNames {
+		vertexKeys[name] = "v" + strconv.Itoa(i)
+		document.Keys = append(document.Keys, graphMLKey{ID: vertexKeys[name], For: "node", Name: name, Type: graphMLTypes[vertexKinds[name]]})
+	}
+	for i, name := range arcNames {
+		arcKeys[name] = "a" + strconv.Itoa(i)
+		document.Keys = append(document.Keys, graphMLKey{ID: arcKeys[name], For: "edge", Name: name, Type: graphMLTypes[arcKinds[name]]})
+	}
+	data := func(keys map[string]string, names []string, attributes Attributes) []graphMLData {
+		var result []graphMLData
+		for _, name := range names {
+			if value, ok := attributes[name]; ok {
+				result = append(result, graphMLData{Key: keys[name], Value: formatAttribute(value)})
+			}
+		}
+		return result
+	}
+	g := graphMLGraph{ID: "G", EdgeDefault: "directed"}
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		g.Nodes = append(g.Nodes, graphMLNode{ID: vPtr.Key, Data: data(vertexKeys, vertexNames, vPtr.Attributes)})
+	}
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		for _, aPtr := range vPtr.arcsInInsertionOrder() {
+			if math.IsInf(aPtr.Weight, 0) || math.IsNaN(aPtr.Weight) {
+				return errors.New("Weight of the arc " + vPtr.Key + "-" + aPtr.Dest.Key + " is not finite")
+			}
+			edge := graphMLEdge{ID: "e" + strconv.Itoa(len(g.Edges)), Source: vPtr.Key, Target: aPtr.Dest.Key}
+			edge.Data = append([]graphMLData{{Key: "weight", Value: formatAttribute(aPtr.Weight)}}, data(arcKeys, arcNames, aPtr.Attributes)...)
+			g.Edges = append(g.Edges, edge)
+		}
+	}
+	document.Graphs = []graphMLGraph{g}
+	return writeXML(w, document)
+}
+
+// Write an XML document with a declaration.
+func writeXML(w io.Writer, document interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Read a GraphML document. Nodes become vertices and edges become arcs; an undirected edge becomes an arc in both
+// directions. The weight of an edge is its data for the key named "weight", otherwise 1; nodes keep their data
+// for a key "weight" declared for all elements as an attribute. Other data become attributes typed after their
+// keys: int and long as int64, float and double as float64, boolean as bool and string as string. Data that cannot be mapped, such as yEd graphics or undeclared keys, and unknown XML are
+// returned as unknown attributes. Only the first graph is read; nested graphs and hyperedges are errors.
+func ReadGraphML(r io.Reader) (*Graph, []UnknownAttribute, error) {
+	var document graphMLDocument
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, nil, errors.New("GraphML: " + err.Error())
+	}
+	if len(document.Graphs) == 0 {
+		return nil, nil, errors.New("GraphML: no graph found")
+	}
+	if len(document.Graphs) > 1 {
+		return nil, nil, errors.New("GraphML: only one graph can be read")
+	}
+	g := document.Graphs[0]
+	if len(g.Hyperedges) > 0 {
+		return nil, nil, errors.New("GraphML: hyperedges are not supported")
+	}
+	unknown := &unknownAttributes{}
+
+	type key struct {
+		element, name, kind string
+		fallback            interface{}
+	}
+	keys := make(map[string]key)
+	weightKey, defaultWeight := "", 1.0
+	for _, k := range document.Keys {
+		element := k.For
+		if element == "" {
+			element = "all"
+		}
+		name := k.Name
+		if name == "" {
+			name = k.ID
+		}
+		if k.YFilesType != "" {
+			keys[k.ID] = key{element: element, name: name}
+			unknown.add(element, name, "yFiles data "+k.YFilesType)
+			continue
+		}
+		kind := ""
+		switch k.Type {
+		case "", "string":
+			kind = attributeString
+		case "int", "long":
+			kind = attributeInt
+		case "float", "double":
+			kind = attributeFloat
+		case "boolean":
+			kind = attributeBool
+		default:
+			keys[k.ID] = key{element: element, name: name}
+			unknown.add(element, name, "unsupported type "+k.Type)
+			continue
+		}
+		var fallback interface{}
+		if k.Default != nil {
+			value, err := parseAttribute(kind, *k.Default)
+			if err != nil {
+				return nil, nil, errors.New("GraphML: invalid default of the key " + k.ID + ": " + err.Error())
+			}
+			fallback = value
+		}
+		if name == "weight" && (element == "edge" || element == "all") && kind != attributeString && kind != attributeBool {
+			weightKey = k.ID
+			if fallback != nil {
+				defaultWeight, _ = strconv.ParseFloat(formatAttribute(fallback), 64)
+			}
+			if element == "edge" {
+				continue
+			}
+			element = "node" // the weight of nodes is kept as an attribute
+		}
+		keys[k.ID] = key{element: element, name: name, kind: kind, fallback: fallback}
+	}
+	// Return the attributes given by the data of an element, with the defaults of the keys.
+	attributes := func(element string, data []graphMLData) (Attributes, error) {
+		var result Attributes
+		set := func(name string, value interface{}) {
+			if result == nil {
+				result = make(Attributes)
+			}
+			result[name] = value
+		}
+		for _, k := range keys {
+			if k.fallback != nil && (k.element == element || k.element == "all") {
+				set(k.name, k.fallback)
+			}
+		}
+		for _, d := range data {
+			if d.Key == weightKey && element == "edge" {
+				continue
+			}
+			k, ok := keys[d.Key]
+			if !ok {
+				unknown.add(element, d.Key, "undeclared key")
+				continue
+			}
+			if k.kind == "" {
+				continue // already reported
+			}
+			if len(d.Children) > 0 {
+				unknown.add(element, k.name, "structured data")
+				continue
+			}
+			value, err := parseAttribute(k.kind, d.Value)
+			if err != nil {
+				return nil, errors.New("GraphML: invalid " + k.name + " of a " + element + ": " + err.Error())
+			}
+			set(k.name, value)
+		}
+		return result, nil
+	}
+
+	for _, d := range g.Data {
+		if k, ok := keys[d.Key]; ok {
+			unknown.add("graph", k.name, "graph data")
+		} else {
+			unknown.add("graph", d.Key, "undeclared key")
+		}
+	}
+	unknown.addXML("graph", g.OtherAttrs, g.Other)
+	builder := newGraphBuilder()
+	for _, node := range g.Nodes {
+		if node.ID == "" {
+			return nil, nil, errors.New("GraphML: node without id")
+		}
+		if _, ok := builder.vertices[node.ID]; ok {
+			return nil, nil, errors.New("GraphML: duplicate node " + node.ID)
+		}
+		if len(node.Graphs) > 0 {
+			return nil, nil, errors.New("GraphML: nested graphs are not supported")
+		}
+		unknown.addXML("node", node.OtherAttrs, node.Other)
+		vPtr := builder.vertex(node.ID)
+		var err error
+		if vPtr.Attributes, err = attributes("node", node.Data); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, edge := range g.Edges {
+		from, to := builder.vertices[edge.Source], builder.vertices[edge.Target]
+		if from == nil || to == nil {
+			return nil, nil, errors.New("GraphML: edge " + edge.Source + "-" + edge.Target + " between unknown nodes")
+		}
+		unknown.addXML("edge", edge.OtherAttrs, edge.Other)
+		weight := defaultWeight
+		for _, d := range edge.Data {
+			if d.Key == weightKey {
+				var err error
+				if weight, err = strconv.ParseFloat(strings.TrimSpace(d.Value), 64); err != nil {
+					return nil, nil, errors.New("GraphML: invalid weight of the edge " + edge.Source + "-" + edge.Target)
+				}
+			}
+		}
+		attrs, err := attributes("edge", edge.Data)
+		if err != nil {
+			return nil, nil, err
+		}
+		directed := g.EdgeDefault != "undirected"
+		if edge.Directed != "" {
+			directed = edge.Directed == "true"
+		}
+		insertEdge(from, to, weight, attrs, directed)
+	}
+	return builder.graph, unknown.list, nil
+}
@@ -0,0 +1,97 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Return a graph with attributes of every kind.
+func attributedGraph() *graph.Graph {
+	g := graph.NewGraph()
+	initGraph(g)
+	g.First.Attributes = graph.Attributes{"name": "Alpha", "zone": int64(2), "x": 1.5, "open": true}
+	g.First.NextVertex.Attributes = graph.Attributes{"name": "Beta"}
+	g.First.Arc.Attributes = graph.Attributes{"line": "red"}
+	return g
+}
+
+func TestGraphMLRoundTrip(t *testing.T) {
+	fmt.Println("Testing GraphML round trips")
+	g := attributedGraph()
+	var out strings.Builder
+	if err := g.WriteGraphML(&out); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	if !strings.Contains(out.String(), `<key id="v3" for="node" attr.name="zone" attr.type="long"></key>`) {
+		t.Errorf("The attribute zone should be declared as a long:\n%s", out.String())
+	}
+	parsed, unknown, err := graph.ReadGraphML(strings.NewReader(out.String()))
+	if err != nil || len(unknown) != 0 || !reflect.DeepEqual(parsed, g) {
+		t.Errorf("The GraphML should be read back into the same graph: %v %v", err, unknown)
+	}
+}
+
+func TestReadGraphML(t *testing.T) {
+	fmt.Println("Testing GraphML import")
+	document := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:y="http://www.yworks.com/xml/graphml">
+  <key id="d0" for="node" attr.name="population" attr.type="int"><default>0</default></key>
+  <key id="d1" for="edge" attr.name="weight" attr.type="double"><default>2</default></key>
+  <key id="d2" for="node" yfiles.type="nodegraphics"/>
+  <key id="d3" for="edge" attr.name="toll" attr.type="boolean"/>
+  <graph id="G" edgedefault="undirected">
+    <node id="a"><data key="d0">120</data><data key="d2"><y:ShapeNode/></data></node>
+    <node id="b"/>
+    <node id="c"><data key="d9">?</data></node>
+    <edge source="a" target="b"><data key="d1">3.5</data><data key="d3">true</data></edge>
+    <edge source="b" target="c" directed="true"/>
+  </graph>
+</graphml>`
+	g, unknown, err := graph.ReadGraphML(strings.NewReader(document))
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	distance, err := g.FindDistance([]string{"b", "a", "b", "c"})
+	if err != nil || distance != 9 {
+		t.Errorf("Undirected edges should become arcs both ways: %v %v", distance, err)
+	}
+	if _, err := g.FindDistance([]string{"c", "b"}); err == nil {
+		t.Errorf("The directed edge should become one arc")
+	}
+	if g.First.Attributes["population"] != int64(120) || g.First.NextVertex.Attributes["population"] != int64(0) {
+		t.Errorf("Node data and defaults should be typed attributes: %v", g.First.Attributes)
+	}
+	if g.First.Arc.Attributes["toll"] != true {
+		t.Errorf("Edge data should be typed attributes")
+	}
+	expected := []graph.UnknownAttribute{
+		{Element: "node", Name: "d2", Reason: "yFiles data nodegraphics"},
+		{Element: "node", Name: "d9", Reason: "undeclared key"},
+	}
+	if !reflect.DeepEqual(unknown, expected) {
+		t.Errorf("Unknown attributes should be %v, not %v", expected, unknown)
+	}
+
+	// a weight declared for all elements is the weight of the edges and an attribute of the nodes
+	document = `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="w" for="all" attr.name="weight" attr.type="double"/>
+  <graph edgedefault="directed">
+    <node id="a"><data key="w">3</data></node>
+    <node id="b"/>
+    <edge source="a" target="b"><data key="w">5</data></edge>
+  </graph>
+</graphml>`
+	g, unknown, err = graph.ReadGraphML(strings.NewReader(document))
+	if err != nil || len(unknown) != 0 {
+		t.Errorf("Error should be NIL: %v %v", err, unknown)
+		return
+	}
+	if g.First.Attributes["weight"] != 3.0 || g.First.Arc.Weight != 5 || g.First.Arc.Attributes != nil {
+		t.Errorf("The node weight should be kept apart from the edge weight: %v %v", g.First.Attributes, g.First.Arc)
+	}
+}
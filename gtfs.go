@@ -0,0 +1,321 @@
+package graph
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GTFSError reports a malformed row of a GTFS file.
+type GTFSError struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (err *GTFSError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", err.File, err.Line, err.Message)
+}
+
+// GTFSFeed is the network read from a GTFS feed.
+type GTFSFeed struct {
+	Graph     *Graph     // stations as vertices, consecutive stops as arcs weighted by the shortest ride in seconds
+	Timetable *Timetable // trips and transfers with times in seconds after midnight; nil unless requested
+}
+
+// A GTFS table being read row by row.
+type gtfsTable struct {
+	file    string
+	reader  *csv.Reader
+	columns map[string]int
+	row     []string
+	line    int
+}
+
+// Read the table 'file' of the feed and call 'process' for every row. The table must have the columns 'required'.
+// When 'optional' is true, a missing table is not an error.
+func readGTFSTable(open func(name string) (io.ReadCloser, error), file string, required []string, optional bool, process func(table *gtfsTable) error) error {
+	rc, err := open(file)
+	if err != nil {
+		if optional && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer rc.Close()
+	table := &gtfsTable{file: file, reader: csv.NewReader(rc), columns: make(map[string]int)}
+	table.reader.FieldsPerRecord = -1
+	header, err := table.reader.Read()
+	if err != nil {
+		return &GTFSError{File: file, Line: 1, Message: "cannot read header: " + err.Error()}
+	}
+	for i, name := range header {
+		table.columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, name := range required {
+		if _, ok := table.columns[name]; !ok {
+			return &GTFSError{File: file, Line: 1, Message: "missing column " + name}
+		}
+	}
+	for {
+		row, err := table.reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			line := table.line + 1
+			if parseErr, ok := err.(*csv.ParseError); ok {
+				line = parseErr.Line
+			}
+			return &GTFSError{File: file, Line: line, Message: err.Error()}
+		}
+		table.row = row
+		table.line, _ = table.reader.FieldPos(0)
+		if err := process(table); err != nil {
+			return err
+		}
+	}
+}
+
+// Return the value of the column 'name' in the current row, or "" when the column is absent.
+func (table *gtfsTable) get(name string) string {
+	if i, ok := table.columns[name]; ok && i < len(table.row) {
+		return strings.TrimSpace(table.row[i])
+	}
+	return ""
+}
+
+// Return an error about the current row.
+func (table *gtfsTable) errorf(format string, args ...interface{}) error {
+	return &GTFSError{File: table.file, Line: table.line, Message: fmt.Sprintf(format, args...)}
+}
+
+// Parse a GTFS time such as "25:10:00" into seconds after midnight. Hours may exceed 24.
+func parseGTFSTime(value string) (float64, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0, errors.New("invalid time " + strconv.Quote(value))
+	}
+	seconds := 0
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, errors.New("invalid time " + strconv.Quote(value))
+		}
+		seconds = seconds*60 + n
+	}
+	return float64(seconds), nil
+}
+
+// Read a GTFS feed from the directory or zip file 'path'. Stops belonging to a parent station are merged into
+// the station. The feed's stops, routes, trips and stop_times are required; transfers are optional. Stops without
+// times are skipped, since times are not interpolated. When 'withTimetable' is true, the timetable is kept in the
+// result too. Malformed rows are reported with a *GTFSError.
+func LoadGTFS(path string, withTimetable bool) (*GTFSFeed, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var open func(name string) (io.ReadCloser, error)
+	if info.IsDir() {
+		open = func(name string) (io.ReadCloser, error) {
+			return os.Open(filepath.Join(path, name))
+		}
+	} else {
+		archive, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		defer archive.Close()
+		open = func(name string) (io.ReadCloser, error) {
+			for _, f := range archive.File {
+				if f.Name == name || strings.HasSuffix(f.Name, "/"+name) {
+					return f.Open()
+				}
+			}
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+	}
+
+	// stops: map every stop to its station
+	station := make(map[string]string)
+	stopLine := make(map[string]int)
+	var stations []string
+	err = readGTFSTable(open, "stops.txt", []string{"stop_id"}, false, func(table *gtfsTable) error {
+		id := table.get("stop_id")
+		if id == "" {
+			return table.errorf("empty stop_id")
+		}
+		if _, ok := station[id]; ok {
+			return table.errorf("duplicate stop_id %s", id)
+		}
+		station[id] = id
+		if parent := table.get("parent_station"); parent != "" {
+			station[id] = parent
+		}
+		stations = append(stations, id)
+		stopLine[id] = table.line
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range stations {
+		if _, ok := station[station[id]]; !ok {
+			return nil, &GTFSError{File: "stops.txt", Line: stopLine[id], Message: "unknown parent_station " + station[id]}
+		}
+	}
+	for _, id := range stations { // follow the parents up to the station
+		seen := map[string]bool{id: true}
+		root := station[id]
+		for station[root] != root {
+			if seen[root] {
+				return nil, &GTFSError{File: "stops.txt", Line: stopLine[id], Message: "parent_station of " + id + " leads to a cycle"}
+			}
+			seen[root] = true
+			root = station[root]
+		}
+		station[id] = root
+	}
+
+	routes := make(map[string]bool)
+	err = readGTFSTable(open, "routes.txt", []string{"route_id"}, false, func(table *gtfsTable) error {
+		if table.get("route_id") == "" {
+			return table.errorf("empty route_id")
+		}
+		routes[table.get("route_id")] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tripRoute := make(map[string]string)
+	var tripIDs []string
+	err = readGTFSTable(open, "trips.txt", []string{"route_id", "trip_id"}, false, func(table *gtfsTable) error {
+		id, route := table.get("trip_id"), table.get("route_id")
+		if id == "" {
+			return table.errorf("empty trip_id")
+		}
+		if !routes[route] {
+			return table.errorf("unknown route_id %s", route)
+		}
+		if _, ok := tripRoute[id]; ok {
+			return table.errorf("duplicate trip_id %s", id)
+		}
+		tripRoute[id] = route
+		tripIDs = append(tripIDs, id)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type sequencedStop struct {
+		sequence int
+		line     int
+		stop     StopTime
+	}
+	stopTimes := make(map[string][]sequencedStop)
+	err = readGTFSTable(open, "stop_times.txt", []string{"trip_id", "arrival_time", "departure_time", "stop_id", "stop_sequence"}, false, func(table *gtfsTable) error {
+		trip, stop := table.get("trip_id"), table.get("stop_id")
+		if _, ok := tripRoute[trip]; !ok {
+			return table.errorf("unknown trip_id %s", trip)
+		}
+		if _, ok := station[stop]; !ok {
+			return table.errorf("unknown stop_id %s", stop)
+		}
+		sequence, err := strconv.Atoi(table.get("stop_sequence"))
+		if err != nil || sequence < 0 {
+			return table.errorf("invalid stop_sequence %q", table.get("stop_sequence"))
+		}
+		arrivalText, departureText := table.get("arrival_time"), table.get("departure_time")
+		if arrivalText == "" && departureText == "" {
+			return nil
+		}
+		if arrivalText == "" {
+			arrivalText = departureText
+		}
+		if departureText == "" {
+			departureText = arrivalText
+		}
+		arrival, err := parseGTFSTime(arrivalText)
+		if err != nil {
+			return table.errorf("arrival_time: %v", err)
+		}
+		departure, err := parseGTFSTime(departureText)
+		if err != nil {
+			return table.errorf("departure_time: %v", err)
+		}
+		if departure < arrival {
+			return table.errorf("departure_time is before arrival_time")
+		}
+		stopTimes[trip] = append(stopTimes[trip], sequencedStop{sequence: sequence, line: table.line,
+			stop: StopTime{StopKey: station[stop], Arrival: arrival, Departure: departure}})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timetable := NewTimetable()
+	for _, id := range tripIDs {
+		stops := stopTimes[id]
+		if len(stops) < 2 {
+			continue
+		}
+		sort.SliceStable(stops, func(a, b int) bool { return stops[a].sequence < stops[b].sequence })
+		times := make([]StopTime, len(stops))
+		for i, s := range stops {
+			if i > 0 && s.stop.Arrival < stops[i-1].stop.Departure {
+				return nil, &GTFSError{File: "stop_times.txt", Line: s.line, Message: "trip " + id + " arrives before leaving the previous stop"}
+			}
+			times[i] = s.stop
+		}
+		// the rows are checked above, but a trip the timetable refuses must not be dropped silently
+		if err := timetable.AddTrip(id, tripRoute[id], times); err != nil {
+			return nil, &GTFSError{File: "stop_times.txt", Line: stops[0].line, Message: err.Error()}
+		}
+	}
+
+	// transfers between different stations; transfer_type 3 means that no transfer is possible
+	err = readGTFSTable(open, "transfers.txt", []string{"from_stop_id", "to_stop_id"}, true, func(table *gtfsTable) error {
+		from, okFrom := station[table.get("from_stop_id")]
+		to, okTo := station[table.get("to_stop_id")]
+		if !okFrom || !okTo {
+			return table.errorf("unknown stop in transfer %s-%s", table.get("from_stop_id"), table.get("to_stop_id"))
+		}
+		if table.get("transfer_type") == "3" || from == to {
+			return nil
+		}
+		duration := 0.0
+		if text := table.get("min_transfer_time"); text != "" {
+			var err error
+			duration, err = strconv.ParseFloat(text, 64)
+			if err != nil || duration < 0 {
+				return table.errorf("invalid min_transfer_time %q", text)
+			}
+		}
+		return timetable.AddTransfer(from, to, duration)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(stations))
+	for i, id := range stations {
+		keys[i] = station[id]
+	}
+	feed := &GTFSFeed{Graph: timetable.buildGraph(keys)}
+	if withTimetable {
+		feed.Timetable = timetable
+	}
+	return feed, nil
+}
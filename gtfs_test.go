@@ -0,0 +1,100 @@
+package graph_test
+
+import (
+	"archive/zip"
+	"fmt"
+	"github.com/audathuynh/graph"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var gtfsFiles = map[string]string{
+	"stops.txt": "stop_id,stop_name,parent_station\n" +
+		"A,Alpha,\nB,Beta,\nB1,Beta platform 1,B\nC,Gamma,\nD,Delta,\n",
+	"routes.txt": "route_id,route_short_name\nR1,1\nR2,2\n",
+	"trips.txt":  "route_id,service_id,trip_id\nR1,S,T1\nR1,S,T2\nR2,S,T3\n",
+	"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n" +
+		"T1,08:00:00,08:00:00,A,1\nT1,08:10:00,08:11:00,B1,2\nT1,08:20:00,08:20:00,C,3\n" +
+		"T2,09:00:00,09:00:00,A,1\nT2,09:08:00,09:08:00,B,2\n" +
+		"T3,08:15:00,08:15:00,B,1\nT3,08:30:00,08:30:00,D,2\n",
+	"transfers.txt": "from_stop_id,to_stop_id,transfer_type,min_transfer_time\nC,D,2,120\n",
+}
+
+func writeGTFS(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadGTFSDirectory(t *testing.T) {
+	fmt.Println("Testing GTFS import from a directory")
+	feed, err := graph.LoadGTFS(writeGTFS(t, gtfsFiles), true)
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	if feed.Graph.Count != 4 {
+		t.Errorf("The platform should be merged into its station")
+	}
+	distance, err := feed.Graph.FindDistance([]string{"A", "B", "C"})
+	if err != nil || distance != 8*60+9*60 {
+		t.Errorf("Arcs should be weighted by the shortest ride")
+	}
+	journey, err := feed.Timetable.EarliestArrival("A", "D", 8*3600)
+	if err != nil || journey.Arrival != 8*3600+22*60 {
+		t.Errorf("The journey should use the transfer from C to D")
+	}
+}
+
+func TestLoadGTFSZip(t *testing.T) {
+	fmt.Println("Testing GTFS import from a zip file")
+	path := filepath.Join(t.TempDir(), "feed.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(f)
+	for name, content := range gtfsFiles {
+		w, _ := archive.Create(name)
+		w.Write([]byte(content))
+	}
+	archive.Close()
+	f.Close()
+
+	feed, err := graph.LoadGTFS(path, false)
+	if err != nil || feed.Timetable != nil || feed.Graph.Count != 4 {
+		t.Errorf("The zip feed should be loaded without its timetable")
+	}
+}
+
+func TestLoadGTFSMalformed(t *testing.T) {
+	fmt.Println("Testing GTFS import of a malformed feed")
+	files := make(map[string]string)
+	for name, content := range gtfsFiles {
+		files[name] = content
+	}
+	files["stop_times.txt"] = "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n" +
+		"T1,08:00:00,08:00:00,A,1\nT1,8h10,08:11:00,B,2\n"
+	_, err := graph.LoadGTFS(writeGTFS(t, files), false)
+	gtfsErr, ok := err.(*graph.GTFSError)
+	if !ok {
+		t.Errorf("Error should be a GTFSError")
+		return
+	}
+	if gtfsErr.File != "stop_times.txt" || gtfsErr.Line != 3 {
+		t.Errorf("Error should point at stop_times.txt line 3: %v", err)
+	}
+
+	files["stop_times.txt"] = gtfsFiles["stop_times.txt"]
+	files["stops.txt"] = "stop_id,stop_name,parent_station\n" +
+		"A,Alpha,\nB,Beta,B2\nB1,Beta platform 1,B\nB2,Beta 2,B1\nC,Gamma,\nD,Delta,\n"
+	_, err = graph.LoadGTFS(writeGTFS(t, files), false)
+	if gtfsErr, ok := err.(*graph.GTFSError); !ok || gtfsErr.File != "stops.txt" || gtfsErr.Line != 3 {
+		t.Errorf("A cycle of parent stations should be reported at stops.txt line 3: %v", err)
+	}
+}
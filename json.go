@@ -0,0 +1,347 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version of the JSON schema written by WriteJSON.
+const jsonSchemaVersion = 1
+
+// JSONError reports a JSON document that does not follow the graph schema. Path locates the offending value,
+// e.g. "$.arcs[3].weight".
+type JSONError struct {
+	Path    string
+	Message string
+}
+
+func (err *JSONError) Error() string {
+	return err.Path + ": " + err.Message
+}
+
+// A vertex in the JSON schema.
+type jsonVertex struct {
+	Key        *string    `json:"key"`
+	Attributes Attributes `json:"attributes,omitempty"`
+}
+
+// An arc in the JSON schema.
+type jsonArc struct {
+	From       *string    `json:"from"`
+	To         *string    `json:"to"`
+	Weight     *float64   `json:"weight"`
+	Capacity   *float64   `json:"capacity,omitempty"`
+	Weights    []float64  `json:"weights,omitempty"`
+	Attributes Attributes `json:"attributes,omitempty"`
+}
+
+// Write the graph as JSON. The schema is
+//
+//	{
+//	  "version": 1,
+//	  "vertices": [{"key": "a", "attributes": {"name": "Alpha"}}, ...],
+//	  "arcs": [{"from": "a", "to": "b", "weight": 5, "capacity": 10, "weights": [5, 2], "attributes": {...}}, ...]
+//	}
+//
+// where "capacity" is left out for arcs without a capacity, and "weights" and "attributes" when they are empty.
+// The first of the "weights" of an arc is its "weight", as for InsertArcWithWeights.
+// Vertices are written in key order and arcs in the order of their origin, one per line, so the same graph is
+// always written the same way. Travel times are not written. Weights must be finite.
+func (graph *Graph) WriteJSON(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	write := func(prefix string, value interface{}) error {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		out.WriteString(prefix)
+		out.Write(data)
+		return nil
+	}
+	fmt.Fprintf(out, "{\"version\":%d,\"vertices\":[", jsonSchemaVersion)
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		prefix := ",\n"
+		if vPtr == graph.First {
+			prefix = "\n"
+		}
+		key := vPtr.Key
+		if err := write(prefix, jsonVertex{Key: &key, Attributes: vPtr.Attributes}); err != nil {
+			return errors.New("Vertex " + vPtr.Key + ": " + err.Error())
+		}
+	}
+	out.WriteString("\n],\"arcs\":[")
+	prefix := "\n"
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		for _, aPtr := range vPtr.arcsInInsertionOrder() {
+			from, to, weight := vPtr.Key, aPtr.Dest.Key, aPtr.Weight
+			arc := jsonArc{From: &from, To: &to, Weight: &weight, Weights: aPtr.Weights, Attributes: aPtr.Attributes}
+			if aPtr.HasCapacity {
+				capacity := aPtr.Capacity
+				arc.Capacity = &capacity
+			}
+			if err := write(prefix, arc); err != nil {
+				return errors.New("Arc " + from + "-" + to + ": " + err.Error())
+			}
+			prefix = ",\n"
+		}
+	}
+	out.WriteString("\n]}\n")
+	return out.Flush()
+}
+
+func (graph *Graph) MarshalJSON() ([]byte, error) {
+	var out strings.Builder
+	if err := graph.WriteJSON(&out); err != nil {
+		return nil, err
+	}
+	return []byte(out.String()), nil
+}
+
+func (graph *Graph) UnmarshalJSON(data []byte) error {
+	parsed, err := ReadJSON(strings.NewReader(string(data)))
+	if err != nil {
+		return err
+	}
+	*graph = *parsed
+	return nil
+}
+
+// Write the attributes as a JSON object with the names in order. Whole float64 values are written with a
+// fraction, e.g. 5.0, so that they are read back as float64 rather than int64.
+func (attributes Attributes) MarshalJSON() ([]byte, error) {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var out strings.Builder
+	out.WriteString("{")
+	for i, name := range names {
+		if i > 0 {
+			out.WriteString(",")
+		}
+		data, _ := json.Marshal(name)
+		out.Write(data)
+		out.WriteString(":")
+		if f, ok := attributes[name].(float64); ok {
+			if math.IsInf(f, 0) || math.IsNaN(f) {
+				return nil, errors.New("attribute " + name + " is not finite")
+			}
+			text := strconv.FormatFloat(f, 'g', -1, 64)
+			if !strings.ContainsAny(text, ".e") {
+				text += ".0"
+			}
+			out.WriteString(text)
+			continue
+		}
+		data, err := json.Marshal(attributes[name])
+		if err != nil {
+			return nil, err
+		}
+		out.Write(data)
+	}
+	out.WriteString("}")
+	return []byte(out.String()), nil
+}
+
+var jsonIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Return the path of the member 'name' of the object at 'path'.
+func jsonMember(path, name string) string {
+	if jsonIdentifier.MatchString(name) {
+		return path + "." + name
+	}
+	return path + "[" + strconv.Quote(name) + "]"
+}
+
+// Turn an error of the JSON decoder into a JSONError about the value at 'path'.
+func jsonDecodeError(path string, err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field != "" {
+			for _, name := range strings.Split(typeErr.Field, ".") {
+				path = jsonMember(path, name)
+			}
+		}
+		return &JSONError{Path: path, Message: "expected " + typeErr.Type.String() + ", found " + typeErr.Value}
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return &JSONError{Path: path, Message: "unexpected end of the document"}
+	}
+	if message := err.Error(); strings.HasPrefix(message, "json: unknown field ") {
+		return &JSONError{Path: path, Message: "unknown member " + strings.TrimPrefix(message, "json: unknown field ")}
+	}
+	return &JSONError{Path: path, Message: strings.TrimPrefix(err.Error(), "json: ")}
+}
+
+// Check the attributes decoded at 'path' and turn their numbers into int64 or float64.
+func checkJSONAttributes(path string, attributes Attributes) error {
+	for name, value := range attributes {
+		switch v := value.(type) {
+		case string, bool:
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				attributes[name] = n
+			} else if f, err := v.Float64(); err == nil {
+				attributes[name] = f
+			} else {
+				return &JSONError{Path: jsonMember(path, name), Message: "invalid number " + v.String()}
+			}
+		default:
+			return &JSONError{Path: jsonMember(path, name), Message: "attribute values must be strings, numbers or booleans"}
+		}
+	}
+	return nil
+}
+
+// Read a graph written in the schema of WriteJSON. The document is read as a stream: vertices and arcs are added
+// as they are decoded, so large graphs are never held in memory twice. Members may come in any order; arcs met
+// before the vertices are kept until the end. Unknown members, missing keys and weights, duplicate vertices and
+// arcs between unknown vertices are reported with a *JSONError giving the path of the offending value.
+func ReadJSON(r io.Reader) (*Graph, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	decoder.DisallowUnknownFields()
+	delimiter := func(path string, want json.Delim) error {
+		token, err := decoder.Token()
+		if err != nil {
+			return jsonDecodeError(path, err)
+		}
+		if token != want {
+			return &JSONError{Path: path, Message: fmt.Sprintf("expected %q, found %v", want.String(), token)}
+		}
+		return nil
+	}
+	if err := delimiter("$", '{'); err != nil {
+		return nil, err
+	}
+
+	builder := newGraphBuilder()
+	seen := make(map[string]bool)
+	type pendingArc struct {
+		path string
+		arc  jsonArc
+	}
+	var pending []pendingArc
+	addArc := func(path string, arc jsonArc) error {
+		from, to := builder.vertices[*arc.From], builder.vertices[*arc.To]
+		if from == nil {
+			return &JSONError{Path: path + ".from", Message: "unknown vertex " + strconv.Quote(*arc.From)}
+		}
+		if to == nil {
+			return &JSONError{Path: path + ".to", Message: "unknown vertex " + strconv.Quote(*arc.To)}
+		}
+		aPtr := linkArc(from, to, *arc.Weight)
+		aPtr.Weights, aPtr.Attributes = arc.Weights, arc.Attributes
+		if arc.Capacity != nil {
+			aPtr.Capacity, aPtr.HasCapacity = *arc.Capacity, true
+		}
+		return nil
+	}
+	verticesRead, versionRead := false, false
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, jsonDecodeError("$", err)
+		}
+		name, _ := token.(string)
+		if seen[name] {
+			return nil, &JSONError{Path: jsonMember("$", name), Message: "duplicate member"}
+		}
+		seen[name] = true
+		switch name {
+		case "version":
+			var version json.Number
+			if err := decoder.Decode(&version); err != nil {
+				return nil, jsonDecodeError("$.version", err)
+			}
+			if version.String() != strconv.Itoa(jsonSchemaVersion) {
+				return nil, &JSONError{Path: "$.version", Message: "unsupported version " + version.String()}
+			}
+			versionRead = true
+		case "vertices":
+			if err := delimiter("$.vertices", '['); err != nil {
+				return nil, err
+			}
+			for i := 0; decoder.More(); i++ {
+				path := fmt.Sprintf("$.vertices[%d]", i)
+				var vertex jsonVertex
+				if err := decoder.Decode(&vertex); err != nil {
+					return nil, jsonDecodeError(path, err)
+				}
+				if vertex.Key == nil {
+					return nil, &JSONError{Path: path, Message: "missing member key"}
+				}
+				if _, ok := builder.vertices[*vertex.Key]; ok {
+					return nil, &JSONError{Path: path + ".key", Message: "duplicate vertex " + strconv.Quote(*vertex.Key)}
+				}
+				if err := checkJSONAttributes(path+".attributes", vertex.Attributes); err != nil {
+					return nil, err
+				}
+				builder.vertex(*vertex.Key).Attributes = vertex.Attributes
+			}
+			if err := delimiter("$.vertices", ']'); err != nil {
+				return nil, err
+			}
+			verticesRead = true
+		case "arcs":
+			if err := delimiter("$.arcs", '['); err != nil {
+				return nil, err
+			}
+			for i := 0; decoder.More(); i++ {
+				path := fmt.Sprintf("$.arcs[%d]", i)
+				var arc jsonArc
+				if err := decoder.Decode(&arc); err != nil {
+					return nil, jsonDecodeError(path, err)
+				}
+				for _, member := range []struct {
+					name  string
+					value bool
+				}{{"from", arc.From != nil}, {"to", arc.To != nil}, {"weight", arc.Weight != nil}} {
+					if !member.value {
+						return nil, &JSONError{Path: path, Message: "missing member " + member.name}
+					}
+				}
+				if err := checkJSONAttributes(path+".attributes", arc.Attributes); err != nil {
+					return nil, err
+				}
+				if arc.Weights != nil && (len(arc.Weights) == 0 || arc.Weights[0] != *arc.Weight) {
+					return nil, &JSONError{Path: path + ".weights", Message: "the first of the weights must be the weight"}
+				}
+				if !verticesRead {
+					pending = append(pending, pendingArc{path: path, arc: arc})
+				} else if err := addArc(path, arc); err != nil {
+					return nil, err
+				}
+			}
+			if err := delimiter("$.arcs", ']'); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, &JSONError{Path: jsonMember("$", name), Message: "unknown member"}
+		}
+	}
+	if err := delimiter("$", '}'); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, &JSONError{Path: "$", Message: "unexpected data after the graph"}
+	}
+	if !versionRead {
+		return nil, &JSONError{Path: "$", Message: "missing member version"}
+	}
+	for _, p := range pending {
+		if err := addArc(p.path, p.arc); err != nil {
+			return nil, err
+		}
+	}
+	return builder.graph, nil
+}
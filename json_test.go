@@ -0,0 +1,79 @@
+package graph_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteJSON(t *testing.T) {
+	fmt.Println("Testing JSON encoding")
+	g := graph.NewGraph()
+	g.InsertVertex("b")
+	g.InsertVertex("a")
+	g.InsertArc("a", "b", 5)
+	g.InsertArcWithCapacity("b", "a", 10, 2.5)
+	g.First.Attributes = graph.Attributes{"name": "Alpha", "zone": int64(2), "x": 1.0, "open": true}
+	var out strings.Builder
+	if err := g.WriteJSON(&out); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	expected := `{"version":1,"vertices":[
+{"key":"a","attributes":{"name":"Alpha","open":true,"x":1.0,"zone":2}},
+{"key":"b"}
+],"arcs":[
+{"from":"a","to":"b","weight":5},
+{"from":"b","to":"a","weight":2.5,"capacity":10}
+]}
+`
+	if out.String() != expected {
+		t.Errorf("The JSON should be\n%s\nnot\n%s", expected, out.String())
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	fmt.Println("Testing JSON round trips")
+	g := graph.NewGraph()
+	initGraph(g)
+	g.InsertArc("a", "b", 1)
+	g.InsertArcWithWeights("e", "a", []float64{1, 2})
+	g.InsertArcWithCapacity("c", "a", 0, 3) // a capacity of 0 is kept
+	g.First.Arc.Attributes = graph.Attributes{"line": "red", "stops": int64(3), "length": 4.0}
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	var parsed graph.Graph
+	if err := json.Unmarshal(data, &parsed); err != nil || !reflect.DeepEqual(&parsed, g) {
+		t.Errorf("The JSON should be decoded into the same graph: %v", err)
+	}
+}
+
+func TestReadJSONErrors(t *testing.T) {
+	fmt.Println("Testing JSON schema errors")
+	for _, c := range []struct {
+		document, path string
+	}{
+		{`{"version":2}`, "$.version"},
+		{`{"version":1,"vertices":[{"key":"a"},{"name":"b"}]}`, "$.vertices[1]"},
+		{`{"version":1,"vertices":[{"key":"a"},{"key":"a"}]}`, "$.vertices[1].key"},
+		{`{"version":1,"vertices":[{"key":"a"}],"arcs":[{"from":"a","to":"a","weight":"5"}]}`, "$.arcs[0].weight"},
+		{`{"version":1,"arcs":[{"from":"a","to":"b","weight":1}],"vertices":[{"key":"a"}]}`, "$.arcs[0].to"},
+		{`{"version":1,"vertices":[{"key":"a","attributes":{"a b":[1]}}]}`, `$.vertices[0].attributes["a b"]`},
+		{`{"version":1,"vertices":[{"key":"a"}],"arcs":[{"from":"a","to":"a","weight":5,"weights":[4,2]}]}`, "$.arcs[0].weights"},
+		{`{"version":1,"vertices":[{"key":"a"}],"arcs":[{"from":"a","to":"a","weight":5,"weights":[]}]}`, "$.arcs[0].weights"},
+		{`{"version":1,"edges":[]}`, "$.edges"},
+		{`{"version":1,"vertices":[{"key":"a"}`, "$.vertices[1]"},
+	} {
+		_, err := graph.ReadJSON(strings.NewReader(c.document))
+		jsonErr, ok := err.(*graph.JSONError)
+		if !ok || jsonErr.Path != c.path {
+			t.Errorf("Error of %s should be at %s, not %v", c.document, c.path, err)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Position is a point of a drawing. Y grows downwards, as in SVG.
+type Position struct {
+	X float64
+	Y float64
+}
+
+// A drawing of a graph: the positions of the vertices and the bends of the arcs that do not go straight.
+type drawing struct {
+	positions map[*Vertex]Position
+	bends     map[*Arc][]Position
+}
+
+// Lay the graph out with the force-directed algorithm of Fruchterman and Reingold in a 'width' x 'height' frame.
+// Arcs pull their ends together and all vertices push each other apart; the moves shrink at every one of the
+// 'iterations' steps. Direction is ignored. The initial positions are drawn from 'seed', so a seed always gives
+// the same layout.
+func (graph *Graph) LayoutForceDirected(width, height float64, iterations int, seed int64) map[string]Position {
+	return keyedPositions(graph.forceDirected(width, height, iterations, seed))
+}
+
+// Lay the graph out in layers with the method of Sugiyama in a 'width' x 'height' frame, which suits directed
+// acyclic graphs: arcs point downwards, except the arcs that close cycles.
+func (graph *Graph) LayoutLayered(width, height float64) map[string]Position {
+	return keyedPositions(graph.layered(width, height))
+}
+
+func keyedPositions(d drawing) map[string]Position {
+	result := make(map[string]Position, len(d.positions))
+	for vPtr, p := range d.positions {
+		result[vPtr.Key] = p
+	}
+	return result
+}
+
+func (graph *Graph) forceDirected(width, height float64, iterations int, seed int64) drawing {
+	vertices, index := graph.indexVertices()
+	n := len(vertices)
+	d := drawing{positions: make(map[*Vertex]Position, n)}
+	if n == 0 {
+		return d
+	}
+	random := rand.New(rand.NewSource(seed))
+	pos := make([]Position, n)
+	for i := range pos {
+		pos[i] = Position{X: random.Float64() * width, Y: random.Float64() * height}
+	}
+	k := math.Sqrt(width * height / float64(n)) // ideal distance between vertices
+	temperature := width / 10
+	for step := 0; step < iterations; step++ {
+		move := make([]Position, n)
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				dx, dy := pos[i].X-pos[j].X, pos[i].Y-pos[j].Y
+				distance := math.Max(math.Hypot(dx, dy), 0.01)
+				force := k * k / distance
+				move[i].X += dx / distance * force
+				move[i].Y += dy / distance * force
+				move[j].X -= dx / distance * force
+				move[j].Y -= dy / distance * force
+			}
+		}
+		for i, vPtr := range vertices {
+			for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+				j := index[aPtr.Dest]
+				if i == j {
+					continue
+				}
+				dx, dy := pos[i].X-pos[j].X, pos[i].Y-pos[j].Y
+				distance := math.Max(math.Hypot(dx, dy), 0.01)
+				force := distance * distance / k
+				move[i].X -= dx / distance * force
+				move[i].Y -= dy / distance * force
+				move[j].X += dx / distance * force
+				move[j].Y += dy / distance * force
+			}
+		}
+		// every vertex moves in the direction of its forces, at most by the temperature, and stays in the frame
+		for i := range pos {
+			length := math.Hypot(move[i].X, move[i].Y)
+			if length > 0 {
+				limit := math.Min(length, temperature)
+				pos[i].X = math.Min(width, math.Max(0, pos[i].X+move[i].X/length*limit))
+				pos[i].Y = math.Min(height, math.Max(0, pos[i].Y+move[i].Y/length*limit))
+			}
+		}
+		temperature -= width / 10 / float64(iterations)
+	}
+	for i, vPtr := range vertices {
+		d.positions[vPtr] = pos[i]
+	}
+	return d
+}
+
+// A node of the layered layout: a vertex, or a dummy node where a long arc crosses a layer.
+type layerNode struct {
+	layer    int
+	order    float64 // position within the layer
+	up, down []int   // nodes linked in the layers above and below
+}
+
+// Lay the graph out in layers with the method of Sugiyama:
+//  1. arcs closing cycles, found by a depth-first search, are reversed so that the graph becomes acyclic;
+//  2. every vertex is put in the layer after the deepest of its predecessors;
+//  3. arcs spanning several layers get a dummy node in every layer in between, where they bend;
+//  4. the order of the nodes in every layer is improved with the barycenter heuristic to reduce crossings;
+//  5. layers are spread from top to bottom and the nodes of a layer evenly across the width.
+func (graph *Graph) layered(width, height float64) drawing {
+	vertices, index := graph.indexVertices()
+	n := len(vertices)
+	d := drawing{positions: make(map[*Vertex]Position, n), bends: make(map[*Arc][]Position)}
+	if n == 0 {
+		return d
+	}
+
+	// 1. find the arcs closing cycles
+	reversed := make(map[*Arc]bool)
+	state := make([]int, n) // 0 unvisited, 1 on the stack, 2 done
+	var visit func(i int)
+	visit = func(i int) {
+		state[i] = 1
+		for aPtr := vertices[i].Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			j := index[aPtr.Dest]
+			switch state[j] {
+			case 0:
+				visit(j)
+			case 1:
+				reversed[aPtr] = true
+			}
+		}
+		state[i] = 2
+	}
+	for i := range vertices {
+		if state[i] == 0 {
+			visit(i)
+		}
+	}
+	// the arcs of the acyclic graph, as pairs of vertex positions
+	type link struct {
+		from, to int
+		arc      *Arc
+	}
+	var links []link
+	for i, vPtr := range vertices {
+		for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			j := index[aPtr.Dest]
+			if i == j {
+				continue
+			}
+			if reversed[aPtr] {
+				links = append(links, link{from: j, to: i, arc: aPtr})
+			} else {
+				links = append(links, link{from: i, to: j, arc: aPtr})
+			}
+		}
+	}
+
+	// 2. longest-path layering in topological order
+	layer := make([]int, n)
+	inDegree := make([]int, n)
+	successors := make([][]int, n)
+	for _, l := range links {
+		inDegree[l.to]++
+		successors[l.from] = append(successors[l.from], l.to)
+	}
+	var queue []int
+	for i := range vertices {
+		if inDegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, j := range successors[i] {
+			layer[j] = max(layer[j], layer[i]+1)
+			if inDegree[j]--; inDegree[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+
+	// 3. nodes for the vertices, then dummy nodes along the long arcs
+	nodes := make([]layerNode, n)
+	for i := range vertices {
+		nodes[i] = layerNode{layer: layer[i]}
+	}
+	chains := make(map[*Arc][]int) // dummy nodes of every long arc, from its upper end
+	for _, l := range links {
+		previous := l.from
+		for y := layer[l.from] + 1; y < layer[l.to]; y++ {
+			nodes = append(nodes, layerNode{layer: y})
+			dummy := len(nodes) - 1
+			chains[l.arc] = append(chains[l.arc], dummy)
+			nodes[previous].down = append(nodes[previous].down, dummy)
+			nodes[dummy].up = append(nodes[dummy].up, previous)
+			previous = dummy
+		}
+		nodes[previous].down = append(nodes[previous].down, l.to)
+		nodes[l.to].up = append(nodes[l.to].up, previous)
+	}
+	depth := 0
+	for _, node := range nodes {
+		depth = max(depth, node.layer+1)
+	}
+	layers := make([][]int, depth)
+	for i := range nodes {
+		nodes[i].order = float64(len(layers[nodes[i].layer]))
+		layers[nodes[i].layer] = append(layers[nodes[i].layer], i)
+	}
+
+	// 4. barycenter sweeps, down then up
+	barycenter := func(i int, neighbours []int) float64 {
+		if len(neighbours) == 0 {
+			return nodes[i].order
+		}
+		sum := 0.0
+		for _, j := range neighbours {
+			sum += nodes[j].order
+		}
+		return sum / float64(len(neighbours))
+	}
+	reorder := func(y int, upwards bool) {
+		keys := make(map[int]float64, len(layers[y]))
+		for _, i := range layers[y] {
+			if upwards {
+				keys[i] = barycenter(i, nodes[i].down)
+			} else {
+				keys[i] = barycenter(i, nodes[i].up)
+			}
+		}
+		sort.SliceStable(layers[y], func(a, b int) bool { return keys[layers[y][a]] < keys[layers[y][b]] })
+		for k, i := range layers[y] {
+			nodes[i].order = float64(k)
+		}
+	}
+	for sweep := 0; sweep < 12; sweep++ {
+		for y := 1; y < depth; y++ {
+			reorder(y, false)
+		}
+		for y := depth - 2; y >= 0; y-- {
+			reorder(y, true)
+		}
+	}
+
+	// 5. coordinates
+	position := func(i int) Position {
+		node := nodes[i]
+		return Position{
+			X: width * (node.order + 1) / float64(len(layers[node.layer])+1),
+			Y: height * (float64(node.layer) + 0.5) / float64(depth),
+		}
+	}
+	for i, vPtr := range vertices {
+		d.positions[vPtr] = position(i)
+	}
+	for _, l := range links {
+		chain := chains[l.arc]
+		if len(chain) == 0 {
+			continue
+		}
+		bends := make([]Position, len(chain))
+		for k, i := range chain {
+			bends[k] = position(i)
+		}
+		if reversed[l.arc] { // the bends run from the upper end, which is the destination of a reversed arc
+			for a, b := 0, len(bends)-1; a < b; a, b = a+1, b-1 {
+				bends[a], bends[b] = bends[b], bends[a]
+			}
+		}
+		d.bends[l.arc] = bends
+	}
+	return d
+}
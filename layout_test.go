@@ -0,0 +1,78 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"testing"
+)
+
+func TestLayoutForceDirected(t *testing.T) {
+	fmt.Println("Testing force-directed layout")
+	g := graph.NewGraph()
+	initGraph(g)
+	positions := g.LayoutForceDirected(400, 300, 100, 7)
+	if len(positions) != 5 {
+		t.Errorf("Every vertex should have a position: %v", positions)
+	}
+	for key, p := range positions {
+		if p.X < 0 || p.X > 400 || p.Y < 0 || p.Y > 300 {
+			t.Errorf("Vertex %s should be in the frame: %v", key, p)
+		}
+	}
+	again := g.LayoutForceDirected(400, 300, 100, 7)
+	for key, p := range positions {
+		if again[key] != p {
+			t.Errorf("The same seed should give the same layout: %v and %v", positions, again)
+			break
+		}
+	}
+	for _, a := range []string{"a", "b", "c", "d", "e"} {
+		for _, b := range []string{"a", "b", "c", "d", "e"} {
+			if a < b && positions[a] == positions[b] {
+				t.Errorf("Vertices %s and %s should be apart", a, b)
+			}
+		}
+	}
+	if len(graph.NewGraph().LayoutForceDirected(400, 300, 100, 7)) != 0 {
+		t.Errorf("An empty graph should have no positions")
+	}
+}
+
+func TestLayoutLayered(t *testing.T) {
+	fmt.Println("Testing layered layout")
+	g := graph.NewGraph()
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		g.InsertVertex(key)
+	}
+	g.InsertArc("a", "b", 1)
+	g.InsertArc("a", "c", 1)
+	g.InsertArc("b", "d", 1)
+	g.InsertArc("c", "d", 1)
+	g.InsertArc("a", "e", 1) // spans no layer
+	g.InsertArc("a", "d", 1) // spans a layer
+	positions := g.LayoutLayered(400, 300)
+	for _, arc := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"a", "d"}, {"a", "e"}} {
+		if positions[arc[0]].Y >= positions[arc[1]].Y {
+			t.Errorf("Arc %s-%s should point downwards: %v", arc[0], arc[1], positions)
+		}
+	}
+	if positions["b"].Y != positions["c"].Y || positions["b"].X == positions["c"].X {
+		t.Errorf("Vertices b and c should be side by side: %v", positions)
+	}
+	for key, p := range positions {
+		if p.X <= 0 || p.X >= 400 || p.Y <= 0 || p.Y >= 300 {
+			t.Errorf("Vertex %s should be in the frame: %v", key, p)
+		}
+	}
+
+	// a cycle is broken, and every vertex still gets its own layer
+	g = graph.NewGraph()
+	initGraph(g)
+	positions = g.LayoutLayered(400, 300)
+	if len(positions) != 5 {
+		t.Errorf("Every vertex should have a position: %v", positions)
+	}
+	if positions["a"].Y >= positions["b"].Y {
+		t.Errorf("Vertex a has no predecessor and should be on top: %v", positions)
+	}
+}
@@ -0,0 +1,299 @@
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+// NotBipartiteError is returned when the vertices of a graph cannot be split into two sides
+// such that every arc joins the two sides.
+type NotBipartiteError struct {
+	FromKey string // an arc that joins two vertices of the same side
+	ToKey   string
+}
+
+func (err *NotBipartiteError) Error() string {
+	return "Graph is not bipartite: arc " + err.FromKey + "-" + err.ToKey + " joins vertices of the same side"
+}
+
+// Find a bipartition of the graph, ignoring the direction of the arcs. In every connected part,
+// the first vertex of the vertex list is put on the left side.
+// A *NotBipartiteError is returned when the graph contains a cycle of odd length.
+func (graph *Graph) Bipartition() ([]string, []string, error) {
+	vertices, index := graph.indexVertices()
+	neighbours := undirectedNeighbours(vertices, index)
+	side := make([]int, len(vertices))
+	for i := range side {
+		side[i] = -1
+	}
+	for start := range vertices {
+		if side[start] != -1 {
+			continue
+		}
+		side[start] = 0
+		queue := []int{start}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			for _, w := range neighbours[v] {
+				if side[w] == -1 {
+					side[w] = 1 - side[v]
+					queue = append(queue, w)
+				} else if side[w] == side[v] {
+					return nil, nil, &NotBipartiteError{FromKey: vertices[v].Key, ToKey: vertices[w].Key}
+				}
+			}
+		}
+	}
+	var left, right []string
+	for i, v := range vertices {
+		if side[i] == 0 {
+			left = append(left, v.Key)
+		} else {
+			right = append(right, v.Key)
+		}
+	}
+	return left, right, nil
+}
+
+// Return the neighbours of every vertex when the direction of the arcs is ignored.
+func undirectedNeighbours(vertices []*Vertex, index map[*Vertex]int) [][]int {
+	neighbours := make([][]int, len(vertices))
+	for i, v := range vertices {
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			j := index[aPtr.Dest]
+			neighbours[i] = append(neighbours[i], j)
+			neighbours[j] = append(neighbours[j], i)
+		}
+	}
+	return neighbours
+}
+
+// Split the vertices into the given left side and the remaining right side, and return the cheapest arc weight
+// between every pair of left and right vertices. Direction of the arcs is ignored.
+// When 'leftKeys' is nil, the bipartition is detected with Bipartition.
+func (graph *Graph) bipartiteWeights(leftKeys []string) ([]*Vertex, []*Vertex, [][]float64, error) {
+	if graph.First == nil {
+		return nil, nil, nil, errors.New("Graph is empty")
+	}
+	if leftKeys == nil {
+		var err error
+		leftKeys, _, err = graph.Bipartition()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	isLeft := make(map[*Vertex]bool)
+	for _, key := range leftKeys {
+		v := graph.findVertex(key)
+		if v == nil {
+			return nil, nil, nil, errors.New("Vertex " + key + " not found")
+		}
+		isLeft[v] = true
+	}
+	var left, right []*Vertex
+	position := make(map[*Vertex]int)
+	for v := graph.First; v != nil; v = v.NextVertex {
+		if isLeft[v] {
+			position[v] = len(left)
+			left = append(left, v)
+		} else {
+			position[v] = len(right)
+			right = append(right, v)
+		}
+	}
+	weights := make([][]float64, len(left))
+	for i := range weights {
+		weights[i] = make([]float64, len(right))
+		for j := range weights[i] {
+			weights[i][j] = math.Inf(1)
+		}
+	}
+	for v := graph.First; v != nil; v = v.NextVertex {
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			if isLeft[v] == isLeft[aPtr.Dest] {
+				return nil, nil, nil, &NotBipartiteError{FromKey: v.Key, ToKey: aPtr.Dest.Key}
+			}
+			i, j := position[v], position[aPtr.Dest]
+			if !isLeft[v] {
+				i, j = j, i
+			}
+			weights[i][j] = math.Min(weights[i][j], aPtr.Weight)
+		}
+	}
+	return left, right, weights, nil
+}
+
+// Find a maximum cardinality matching with the Hopcroft-Karp algorithm. The direction of the arcs is ignored.
+// 'leftKeys' is one side of the bipartition; when it is nil, the bipartition is detected automatically.
+// Every matched pair is reported from its left vertex to its right vertex with the weight of the cheapest arc between them.
+func (graph *Graph) FindMaxMatching(leftKeys []string) ([]Edge, error) {
+	left, right, weights, err := graph.bipartiteWeights(leftKeys)
+	if err != nil {
+		return nil, err
+	}
+	adjacent := make([][]int, len(left))
+	for i := range left {
+		for j := range right {
+			if !math.IsInf(weights[i][j], 1) {
+				adjacent[i] = append(adjacent[i], j)
+			}
+		}
+	}
+	matchLeft := make([]int, len(left))
+	matchRight := make([]int, len(right))
+	for i := range matchLeft {
+		matchLeft[i] = -1
+	}
+	for j := range matchRight {
+		matchRight[j] = -1
+	}
+	dist := make([]int, len(left))
+
+	// breadth-first search from the free left vertices builds the layers of shortest augmenting paths
+	bfs := func() bool {
+		var queue []int
+		for i := range left {
+			if matchLeft[i] == -1 {
+				dist[i] = 0
+				queue = append(queue, i)
+			} else {
+				dist[i] = -1
+			}
+		}
+		found := false
+		for len(queue) > 0 {
+			i := queue[0]
+			queue = queue[1:]
+			for _, j := range adjacent[i] {
+				k := matchRight[j]
+				if k == -1 {
+					found = true
+				} else if dist[k] == -1 {
+					dist[k] = dist[i] + 1
+					queue = append(queue, k)
+				}
+			}
+		}
+		return found
+	}
+	// depth-first search along the layers augments the matching
+	var dfs func(i int) bool
+	dfs = func(i int) bool {
+		for _, j := range adjacent[i] {
+			k := matchRight[j]
+			if k == -1 || (dist[k] == dist[i]+1 && dfs(k)) {
+				matchLeft[i] = j
+				matchRight[j] = i
+				return true
+			}
+		}
+		dist[i] = -1
+		return false
+	}
+	for bfs() {
+		for i := range left {
+			if matchLeft[i] == -1 {
+				dfs(i)
+			}
+		}
+	}
+
+	var result []Edge
+	for i, j := range matchLeft {
+		if j != -1 {
+			result = append(result, Edge{FromKey: left[i].Key, ToKey: right[j].Key, Weight: weights[i][j]})
+		}
+	}
+	return result, nil
+}
+
+// Find a perfect matching of minimum total weight with the Hungarian algorithm. The direction of the arcs is ignored.
+// 'leftKeys' is one side of the bipartition; when it is nil, the bipartition is detected automatically.
+// Both sides must have the same number of vertices.
+func (graph *Graph) FindMinWeightPerfectMatching(leftKeys []string) ([]Edge, float64, error) {
+	left, right, weights, err := graph.bipartiteWeights(leftKeys)
+	if err != nil {
+		return nil, 0, err
+	}
+	n := len(left)
+	if n != len(right) {
+		return nil, 0, errors.New("Both sides must have the same number of vertices")
+	}
+	// missing pairs get a cost higher than any perfect matching using real arcs
+	big := 1.0
+	for i := range weights {
+		for j := range weights[i] {
+			if !math.IsInf(weights[i][j], 1) {
+				big += 2 * math.Abs(weights[i][j])
+			}
+		}
+	}
+	cost := func(i, j int) float64 {
+		if math.IsInf(weights[i][j], 1) {
+			return big
+		}
+		return weights[i][j]
+	}
+
+	// potentials u (left) and v (right); rows and columns are numbered from 1, column 0 is a dummy
+	u := make([]float64, n+1)
+	v := make([]float64, n+1)
+	owner := make([]int, n+1) // left vertex matched to every column
+	way := make([]int, n+1)
+	for i := 1; i <= n; i++ {
+		owner[0] = i
+		j0 := 0
+		minv := make([]float64, n+1)
+		used := make([]bool, n+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+		for owner[j0] != 0 {
+			used[j0] = true
+			i0 := owner[j0]
+			delta := math.Inf(1)
+			j1 := 0
+			for j := 1; j <= n; j++ {
+				if used[j] {
+					continue
+				}
+				reduced := cost(i0-1, j-1) - u[i0] - v[j]
+				if reduced < minv[j] {
+					minv[j] = reduced
+					way[j] = j0
+				}
+				if minv[j] < delta {
+					delta = minv[j]
+					j1 = j
+				}
+			}
+			for j := 0; j <= n; j++ {
+				if used[j] {
+					u[owner[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+		}
+		for j0 != 0 {
+			j1 := way[j0]
+			owner[j0] = owner[j1]
+			j0 = j1
+		}
+	}
+
+	result := make([]Edge, n)
+	total := 0.0
+	for j := 1; j <= n; j++ {
+		i := owner[j] - 1
+		if math.IsInf(weights[i][j-1], 1) {
+			return nil, 0, errors.New("No perfect matching found")
+		}
+		result[i] = Edge{FromKey: left[i].Key, ToKey: right[j-1].Key, Weight: weights[i][j-1]}
+		total += weights[i][j-1]
+	}
+	return result, total, nil
+}
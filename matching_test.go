@@ -0,0 +1,81 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"testing"
+)
+
+func initCrewGraph(g *graph.Graph) {
+	for _, key := range []string{"c1", "c2", "c3", "t1", "t2", "t3"} {
+		g.InsertVertex(key)
+	}
+	g.InsertArc("c1", "t1", 4)
+	g.InsertArc("c1", "t2", 1)
+	g.InsertArc("c1", "t3", 3)
+	g.InsertArc("c2", "t1", 2)
+	g.InsertArc("c2", "t3", 5)
+	g.InsertArc("t2", "c3", 3) // direction is ignored
+	g.InsertArc("c3", "t3", 2)
+}
+
+func TestBipartition(t *testing.T) {
+	fmt.Println("Testing bipartition")
+	g := graph.NewGraph()
+	initCrewGraph(g)
+
+	left, right, err := g.Bipartition()
+	if err != nil {
+		t.Errorf("Error should be NIL")
+	}
+	if !reflect.DeepEqual(left, []string{"c1", "c2", "c3"}) || !reflect.DeepEqual(right, []string{"t1", "t2", "t3"}) {
+		t.Errorf("Bipartition is not correct")
+	}
+
+	g = graph.NewGraph()
+	initGraph(g)
+	_, _, err = g.Bipartition()
+	if _, ok := err.(*graph.NotBipartiteError); !ok {
+		t.Errorf("Error should be a NotBipartiteError")
+	}
+}
+
+func TestMaxMatching(t *testing.T) {
+	fmt.Println("Testing maximum matching")
+	g := graph.NewGraph()
+	initCrewGraph(g)
+
+	result, err := g.FindMaxMatching(nil)
+	fmt.Println(result)
+	if err != nil {
+		t.Errorf("Error should be NIL")
+	}
+	if len(result) != 3 {
+		t.Errorf("All crews should be matched")
+	}
+
+	_, err = g.FindMaxMatching([]string{"c1", "c2", "t3"})
+	if _, ok := err.(*graph.NotBipartiteError); !ok {
+		t.Errorf("Error should be a NotBipartiteError")
+	}
+}
+
+func TestMinWeightPerfectMatching(t *testing.T) {
+	fmt.Println("Testing minimum-weight perfect matching")
+	g := graph.NewGraph()
+	initCrewGraph(g)
+
+	result, total, err := g.FindMinWeightPerfectMatching([]string{"c1", "c2", "c3"})
+	fmt.Println(result, total)
+	if err != nil {
+		t.Errorf("Error should be NIL")
+	}
+	expected := []graph.Edge{
+		{FromKey: "c1", ToKey: "t2", Weight: 1},
+		{FromKey: "c2", ToKey: "t1", Weight: 2},
+		{FromKey: "c3", ToKey: "t3", Weight: 2}}
+	if !reflect.DeepEqual(result, expected) || total != 5 {
+		t.Errorf("Solution is not correct")
+	}
+}
@@ -0,0 +1,176 @@
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+// Flows smaller than this are treated as zero.
+const flowEpsilon = 1e-9
+
+// ArcFlow is the flow assigned to one arc of the graph.
+type ArcFlow struct {
+	FromKey  string
+	ToKey    string
+	Capacity float64
+	Flow     float64
+}
+
+// MaxFlowResult is the result of FindMaxFlow.
+type MaxFlowResult struct {
+	Value      float64   // total flow from the source to the sink
+	Flows      []ArcFlow // flow on every arc, in the order of the vertex and arc lists
+	SourceSide []string  // vertices reachable from the source in the residual network
+	SinkSide   []string  // the remaining vertices
+	CutArcs    []Edge    // saturated arcs from the source side to the sink side
+}
+
+// A residual network. Arc i and arc i^1 are the forward and the backward arc of a pair.
+type flowNetwork struct {
+	head  []int
+	next  []int
+	to    []int
+	cap   []float64
+	cost  []float64
+	level []int
+	iter  []int
+}
+
+func newFlowNetwork(n int) *flowNetwork {
+	net := &flowNetwork{head: make([]int, n), level: make([]int, n), iter: make([]int, n)}
+	for i := range net.head {
+		net.head[i] = -1
+	}
+	return net
+}
+
+// Add an arc and its backward arc, and return the position of the forward arc.
+func (net *flowNetwork) addArc(from, to int, capacity, cost float64) int {
+	id := len(net.to)
+	net.to = append(net.to, to, from)
+	net.cap = append(net.cap, capacity, 0)
+	net.cost = append(net.cost, cost, -cost)
+	net.next = append(net.next, net.head[from], net.head[to])
+	net.head[from] = id
+	net.head[to] = id + 1
+	return id
+}
+
+// Build the level graph with a breadth-first search. Return false when the sink is not reachable.
+func (net *flowNetwork) bfs(source, sink int) bool {
+	for i := range net.level {
+		net.level[i] = -1
+	}
+	net.level[source] = 0
+	queue := []int{source}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for e := net.head[v]; e != -1; e = net.next[e] {
+			if net.cap[e] > flowEpsilon && net.level[net.to[e]] < 0 {
+				net.level[net.to[e]] = net.level[v] + 1
+				queue = append(queue, net.to[e])
+			}
+		}
+	}
+	return net.level[sink] >= 0
+}
+
+// Push a blocking flow along the level graph.
+func (net *flowNetwork) dfs(v, sink int, limit float64) float64 {
+	if v == sink {
+		return limit
+	}
+	for ; net.iter[v] != -1; net.iter[v] = net.next[net.iter[v]] {
+		e := net.iter[v]
+		w := net.to[e]
+		if net.cap[e] > flowEpsilon && net.level[w] == net.level[v]+1 {
+			pushed := net.dfs(w, sink, math.Min(limit, net.cap[e]))
+			if pushed > flowEpsilon {
+				net.cap[e] -= pushed
+				net.cap[e^1] += pushed
+				return pushed
+			}
+		}
+	}
+	return 0
+}
+
+// Compute the maximum flow from 'source' to 'sink' with Dinic's algorithm.
+func (net *flowNetwork) maxFlow(source, sink int) float64 {
+	total := 0.0
+	for net.bfs(source, sink) {
+		copy(net.iter, net.head)
+		for {
+			pushed := net.dfs(source, sink, math.Inf(1))
+			if pushed <= flowEpsilon {
+				break
+			}
+			total += pushed
+		}
+	}
+	return total
+}
+
+// Return the capacity of an arc in a maximum flow: its Capacity, or its Weight when it has no capacity, as for
+// the arcs inserted with InsertArc.
+func maxFlowCapacity(aPtr *Arc) float64 {
+	if aPtr.HasCapacity {
+		return aPtr.Capacity
+	}
+	return aPtr.Weight
+}
+
+// Find the maximum flow from the vertex 'sourceKey' to the vertex 'sinkKey' with Dinic's algorithm.
+// Arcs carry at most their Capacity, or their Weight when they have no capacity (see InsertArcWithCapacity).
+// The result includes the flow on each arc and a minimum cut.
+func (graph *Graph) FindMaxFlow(sourceKey, sinkKey string) (*MaxFlowResult, error) {
+	if graph.First == nil {
+		return nil, errors.New("Graph is empty")
+	}
+	source := graph.findVertex(sourceKey)
+	if source == nil {
+		return nil, errors.New("SourceKey not found")
+	}
+	sink := graph.findVertex(sinkKey)
+	if sink == nil {
+		return nil, errors.New("SinkKey not found")
+	}
+	if source == sink {
+		return nil, errors.New("Source and sink must be different")
+	}
+	vertices, index := graph.indexVertices()
+	net := newFlowNetwork(len(vertices))
+	var ids []int
+	for i, v := range vertices {
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			if maxFlowCapacity(aPtr) < 0 {
+				return nil, errors.New("Capacity must not be negative")
+			}
+			ids = append(ids, net.addArc(i, index[aPtr.Dest], maxFlowCapacity(aPtr), 0))
+		}
+	}
+	result := &MaxFlowResult{Value: net.maxFlow(index[source], index[sink])}
+
+	// vertices still reachable from the source form the source side of a minimum cut
+	net.bfs(index[source], index[sink])
+	for i, v := range vertices {
+		if net.level[i] >= 0 {
+			result.SourceSide = append(result.SourceSide, v.Key)
+		} else {
+			result.SinkSide = append(result.SinkSide, v.Key)
+		}
+	}
+	k := 0
+	for i, v := range vertices {
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			flow := net.cap[ids[k]^1]
+			result.Flows = append(result.Flows, ArcFlow{FromKey: v.Key, ToKey: aPtr.Dest.Key, Capacity: maxFlowCapacity(aPtr), Flow: flow})
+			if net.level[i] >= 0 && net.level[index[aPtr.Dest]] < 0 {
+				result.CutArcs = append(result.CutArcs, Edge{FromKey: v.Key, ToKey: aPtr.Dest.Key, Weight: maxFlowCapacity(aPtr)})
+			}
+			k++
+		}
+	}
+	return result, nil
+}
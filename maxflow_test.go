@@ -0,0 +1,105 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"testing"
+)
+
+func TestMaxFlow(t *testing.T) {
+	fmt.Println("Testing maximum flow and minimum cut")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	result, err := g.FindMaxFlow("a", "e")
+	if err != nil {
+		t.Errorf("Error should be NIL")
+	}
+	fmt.Println(result.Value, result.CutArcs)
+	// every arc into "e" is saturated: c-e 2, d-e 6, a-e 7
+	if result.Value != 15 {
+		t.Errorf("The maximum flow should be 15")
+	}
+	cut := 0.0
+	for _, arc := range result.CutArcs {
+		cut += arc.Weight
+	}
+	if cut != result.Value {
+		t.Errorf("The capacity of the cut should be equal to the maximum flow")
+	}
+	if !reflect.DeepEqual(result.SinkSide, []string{"e"}) {
+		t.Errorf("The sink side should only contain e")
+	}
+	for _, flow := range result.Flows {
+		if flow.Flow < 0 || flow.Flow > flow.Capacity {
+			t.Errorf("Flow on %s-%s is not feasible", flow.FromKey, flow.ToKey)
+		}
+	}
+}
+
+func TestMaxFlowKeyNotFound(t *testing.T) {
+	fmt.Println("Testing maximum flow with a missing key")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	_, err := g.FindMaxFlow("a", "x")
+	if err == nil {
+		t.Errorf("Error should NOT be NIL")
+	}
+}
+
+func TestMaxFlowWithCapacity(t *testing.T) {
+	fmt.Println("Testing maximum flow on arcs with capacities")
+	g := graph.NewGraph()
+	g.InsertVertex("s")
+	g.InsertVertex("m")
+	g.InsertVertex("t")
+	g.InsertArcWithCapacity("s", "m", 10, 1)
+	g.InsertArcWithCapacity("m", "t", 4, 1)
+	g.InsertArcWithCapacity("s", "t", 3, 2)
+
+	result, err := g.FindMaxFlow("s", "t")
+	if err != nil {
+		t.Errorf("Error should be NIL")
+	}
+	if result.Value != 7 {
+		t.Errorf("The maximum flow should be 7, got %v", result.Value)
+	}
+	for _, flow := range result.Flows {
+		if flow.FromKey == "s" && flow.ToKey == "m" && flow.Capacity != 10 {
+			t.Errorf("The capacity of s-m should be 10, got %v", flow.Capacity)
+		}
+	}
+	cut := 0.0
+	for _, arc := range result.CutArcs {
+		cut += arc.Weight
+	}
+	if cut != 7 {
+		t.Errorf("The capacity of the cut should be 7, got %v", cut)
+	}
+}
+
+func TestMaxFlowZeroCapacity(t *testing.T) {
+	fmt.Println("Testing maximum and minimum-cost flows through a closed arc")
+	g := graph.NewGraph()
+	g.InsertVertex("s")
+	g.InsertVertex("m")
+	g.InsertVertex("t")
+	g.InsertArcWithCapacity("s", "m", 5, 1)
+	g.InsertArcWithCapacity("m", "t", 0, 1) // closed, whatever its cost
+	g.InsertArcWithCapacity("s", "t", 2, 3)
+
+	result, err := g.FindMaxFlow("s", "t")
+	if err != nil || result.Value != 2 {
+		t.Errorf("The maximum flow should be 2, got %v (%v)", result, err)
+	}
+	_, err = g.FindMinCostFlow(map[string]float64{"s": 3, "t": -3})
+	if _, ok := err.(*graph.InfeasibleFlowError); !ok {
+		t.Errorf("Only 2 units should be routed by the minimum-cost flow either")
+	}
+	flow, err := g.FindMinCostFlow(map[string]float64{"s": 2, "t": -2})
+	if err != nil || flow.Cost != 6 {
+		t.Errorf("The cost should be 6, got %v (%v)", flow, err)
+	}
+}
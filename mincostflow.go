@@ -0,0 +1,188 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// InfeasibleFlowError is returned by FindMinCostFlow when the supplies cannot be routed to the demands.
+type InfeasibleFlowError struct {
+	Supply float64 // total supply
+	Demand float64 // total demand
+	Routed float64 // flow that could be routed within the capacities
+}
+
+func (err *InfeasibleFlowError) Error() string {
+	if math.Abs(err.Supply-err.Demand) > flowEpsilon {
+		return fmt.Sprintf("Infeasible flow: supply %g and demand %g are not balanced", err.Supply, err.Demand)
+	}
+	return fmt.Sprintf("Infeasible flow: only %g of %g units can be routed", err.Routed, err.Supply)
+}
+
+// MinCostFlowResult is the result of FindMinCostFlow.
+type MinCostFlowResult struct {
+	Cost  float64   // total cost of the flow
+	Flows []ArcFlow // flow on every arc, in the order of the vertex and arc lists
+}
+
+// Find the cheapest flow satisfying the supplies and demands in 'supply' with successive shortest paths.
+// A positive value is the supply of a vertex and a negative value is its demand. The supplies and demands must
+// balance. Arcs carry at most their Capacity and cost their Weight per unit of flow.
+// An *InfeasibleFlowError is returned when the demands cannot be satisfied.
+func (graph *Graph) FindMinCostFlow(supply map[string]float64) (*MinCostFlowResult, error) {
+	if graph.First == nil {
+		return nil, errors.New("Graph is empty")
+	}
+	vertices, index := graph.indexVertices()
+	n := len(vertices)
+	source, sink := n, n+1 // a super source and a super sink
+	net := newFlowNetwork(n + 2)
+	var ids []int
+	for i, v := range vertices {
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			if aPtr.Capacity < 0 {
+				return nil, errors.New("Capacity must not be negative")
+			}
+			ids = append(ids, net.addArc(i, index[aPtr.Dest], aPtr.Capacity, aPtr.Weight))
+		}
+	}
+	var missing []string
+	for key := range supply {
+		if graph.findVertex(key) == nil {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, errors.New("Vertex " + missing[0] + " not found")
+	}
+	// the arcs from the super source and to the super sink follow the vertex list, so that ties between
+	// paths of the same cost are always broken the same way
+	required, demand := 0.0, 0.0
+	for i, v := range vertices {
+		amount := supply[v.Key]
+		if amount > 0 {
+			net.addArc(source, i, amount, 0)
+			required += amount
+		} else if amount < 0 {
+			net.addArc(i, sink, -amount, 0)
+			demand -= amount
+		}
+	}
+	if math.Abs(required-demand) > flowEpsilon {
+		return nil, &InfeasibleFlowError{Supply: required, Demand: demand}
+	}
+
+	routed, cost, err := net.minCostFlow(source, sink, required)
+	if err != nil {
+		return nil, err
+	}
+	if routed < required-flowEpsilon {
+		return nil, &InfeasibleFlowError{Supply: required, Demand: demand, Routed: routed}
+	}
+	result := &MinCostFlowResult{Cost: cost}
+	k := 0
+	for _, v := range vertices {
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			result.Flows = append(result.Flows, ArcFlow{FromKey: v.Key, ToKey: aPtr.Dest.Key, Capacity: aPtr.Capacity, Flow: net.cap[ids[k]^1]})
+			k++
+		}
+	}
+	return result, nil
+}
+
+// Send up to 'limit' units from 'source' to 'sink' along successive shortest paths.
+// Vertex potentials keep the reduced costs non-negative so that Dijkstra's algorithm can be used.
+func (net *flowNetwork) minCostFlow(source, sink int, limit float64) (float64, float64, error) {
+	n := len(net.head)
+	potential, err := net.initialPotentials(source)
+	if err != nil {
+		return 0, 0, err
+	}
+	dist := make([]float64, n)
+	prevArc := make([]int, n)
+	done := make([]bool, n)
+	flow, cost := 0.0, 0.0
+	for flow < limit-flowEpsilon {
+		for i := 0; i < n; i++ {
+			dist[i] = math.Inf(1)
+			prevArc[i] = -1
+			done[i] = false
+		}
+		dist[source] = 0
+		for {
+			v := -1
+			for i := 0; i < n; i++ {
+				if !done[i] && !math.IsInf(dist[i], 1) && (v == -1 || dist[i] < dist[v]) {
+					v = i
+				}
+			}
+			if v == -1 {
+				break
+			}
+			done[v] = true
+			for e := net.head[v]; e != -1; e = net.next[e] {
+				w := net.to[e]
+				if net.cap[e] <= flowEpsilon || math.IsInf(potential[w], 1) {
+					continue
+				}
+				reduced := dist[v] + net.cost[e] + potential[v] - potential[w]
+				if reduced < dist[w]-flowEpsilon {
+					dist[w] = reduced
+					prevArc[w] = e
+				}
+			}
+		}
+		if math.IsInf(dist[sink], 1) {
+			break
+		}
+		for i := 0; i < n; i++ {
+			if !math.IsInf(potential[i], 1) {
+				potential[i] += math.Min(dist[i], dist[sink])
+			}
+		}
+		// augment along the path found
+		amount := limit - flow
+		for v := sink; v != source; v = net.to[prevArc[v]^1] {
+			amount = math.Min(amount, net.cap[prevArc[v]])
+		}
+		for v := sink; v != source; v = net.to[prevArc[v]^1] {
+			e := prevArc[v]
+			net.cap[e] -= amount
+			net.cap[e^1] += amount
+			cost += amount * net.cost[e]
+		}
+		flow += amount
+	}
+	return flow, cost, nil
+}
+
+// Compute shortest distances from 'source' with the Bellman-Ford algorithm so that negative costs are allowed.
+func (net *flowNetwork) initialPotentials(source int) ([]float64, error) {
+	n := len(net.head)
+	potential := make([]float64, n)
+	for i := range potential {
+		potential[i] = math.Inf(1)
+	}
+	potential[source] = 0
+	for round := 0; round < n; round++ {
+		changed := false
+		for v := 0; v < n; v++ {
+			if math.IsInf(potential[v], 1) {
+				continue
+			}
+			for e := net.head[v]; e != -1; e = net.next[e] {
+				if net.cap[e] > flowEpsilon && potential[v]+net.cost[e] < potential[net.to[e]]-flowEpsilon {
+					potential[net.to[e]] = potential[v] + net.cost[e]
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			return potential, nil
+		}
+	}
+	return nil, errors.New("Negative cost cycle found")
+}
@@ -0,0 +1,62 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"testing"
+)
+
+func initFlowGraph(g *graph.Graph) {
+	g.InsertVertex("s")
+	g.InsertVertex("a")
+	g.InsertVertex("b")
+	g.InsertVertex("t")
+	g.InsertArcWithCapacity("s", "a", 4, 2)
+	g.InsertArcWithCapacity("s", "b", 2, 2)
+	g.InsertArcWithCapacity("a", "b", 2, 1)
+	g.InsertArcWithCapacity("a", "t", 3, 3)
+	g.InsertArcWithCapacity("b", "t", 5, 1)
+}
+
+func TestMinCostFlow(t *testing.T) {
+	fmt.Println("Testing minimum-cost flow")
+	g := graph.NewGraph()
+	initFlowGraph(g)
+
+	result, err := g.FindMinCostFlow(map[string]float64{"s": 5, "t": -5})
+	if err != nil {
+		t.Errorf("Error should be NIL")
+		return
+	}
+	fmt.Println(result.Cost, result.Flows)
+	// s-b-t 2 units (cost 6), s-a-b-t 2 units (cost 8), s-a-t 1 unit (cost 5)
+	if result.Cost != 19 {
+		t.Errorf("The cost should be 19")
+	}
+}
+
+func TestMinCostFlowInfeasible(t *testing.T) {
+	fmt.Println("Testing infeasible minimum-cost flow")
+	g := graph.NewGraph()
+	initFlowGraph(g)
+
+	_, err := g.FindMinCostFlow(map[string]float64{"s": 7, "t": -7})
+	infeasible, ok := err.(*graph.InfeasibleFlowError)
+	if !ok {
+		t.Errorf("Error should be an InfeasibleFlowError")
+		return
+	}
+	if infeasible.Routed != 6 {
+		t.Errorf("Only 6 units should be routed")
+	}
+	_, err = g.FindMinCostFlow(map[string]float64{"s": 7, "t": -6})
+	if _, ok := err.(*graph.InfeasibleFlowError); !ok {
+		t.Errorf("Unbalanced supplies should be infeasible")
+	}
+	for i := 0; i < 10; i++ {
+		_, err = g.FindMinCostFlow(map[string]float64{"y": 1, "s": 1, "x": -1, "t": -1})
+		if err == nil || err.Error() != "Vertex x not found" {
+			t.Errorf("The first missing vertex should be reported, not %v", err)
+		}
+	}
+}
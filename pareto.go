@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"errors"
+)
+
+// ParetoRoute is a route that no other route beats on every criterion.
+type ParetoRoute struct {
+	Route []string
+	Costs []float64 // total of every criterion along the route
+}
+
+// Return the criteria of an arc: its Weights, or its Weight when it has no Weights.
+func arcCriteria(aPtr *Arc) []float64 {
+	if aPtr.Weights != nil {
+		return aPtr.Weights
+	}
+	return []float64{aPtr.Weight}
+}
+
+// Check whether the costs 'a' are at most the costs 'b' on every criterion.
+func dominates(a, b []float64) bool {
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Check whether the costs 'a' come before the costs 'b' in lexicographic order.
+func lexicographicLess(a, b []float64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// Find the Pareto front of the routes from the vertex 'fromKey' to the vertex 'toKey' with a label-setting search.
+// The criteria of an arc are its Weights (see InsertArcWithWeights); every arc must have the same number of
+// criteria and none may be negative. Routes are returned in lexicographic order of their costs. When 'maxRoutes'
+// is greater than 0, at most 'maxRoutes' routes are returned.
+func (graph *Graph) FindParetoRoutes(fromKey, toKey string, maxRoutes int) ([]ParetoRoute, error) {
+	if graph.First == nil {
+		return nil, errors.New("Graph is empty")
+	}
+	vFromPtr := graph.findVertex(fromKey)
+	if vFromPtr == nil {
+		return nil, errors.New("FromKey not found")
+	}
+	if graph.findVertex(toKey) == nil {
+		return nil, errors.New("ToKey not found")
+	}
+	dimension := -1
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			criteria := arcCriteria(aPtr)
+			if dimension == -1 {
+				dimension = len(criteria)
+			}
+			if len(criteria) != dimension {
+				return nil, errors.New("Arcs have different numbers of weights")
+			}
+			for _, c := range criteria {
+				if c < 0 {
+					return nil, errors.New("Weights must not be negative")
+				}
+			}
+		}
+	}
+	if dimension == -1 {
+		dimension = 1
+	}
+
+	// A label is a copy of a vertex whose Parent is the previous label on its route, as in FindTripExactStops.
+	costs := make(map[*Vertex][]float64)
+	settled := make(map[string][][]float64) // costs of the labels already settled at every vertex
+	isDominated := func(key string, c []float64) bool {
+		for _, other := range settled[key] {
+			if dominates(other, c) {
+				return true
+			}
+		}
+		return false
+	}
+	queue := NewQueue(true)
+	queue.Better = func(a, b *Vertex) bool {
+		return lexicographicLess(costs[a], costs[b])
+	}
+	label := &Vertex{Key: vFromPtr.Key, Arc: vFromPtr.Arc}
+	costs[label] = make([]float64, dimension)
+	queue.Enqueue(label)
+
+	var result []ParetoRoute
+	for !queue.IsEmpty() {
+		queue.Dequeue(&label)
+		c := costs[label]
+		// labels are settled in lexicographic order, so a label is never dominated by a later one
+		if isDominated(label.Key, c) || isDominated(toKey, c) {
+			continue
+		}
+		settled[label.Key] = append(settled[label.Key], c)
+		if label.Key == toKey {
+			result = append(result, ParetoRoute{Route: processSolution(label), Costs: c})
+			if maxRoutes > 0 && len(result) >= maxRoutes {
+				break
+			}
+			continue
+		}
+		for aPtr := label.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			criteria := arcCriteria(aPtr)
+			next := make([]float64, dimension)
+			for i := range next {
+				next[i] = c[i] + criteria[i]
+			}
+			if isDominated(aPtr.Dest.Key, next) || isDominated(toKey, next) {
+				continue
+			}
+			dataPtr := &Vertex{Key: aPtr.Dest.Key, Arc: aPtr.Dest.Arc, Parent: label}
+			costs[dataPtr] = next
+			queue.Enqueue(dataPtr)
+		}
+	}
+	return result, nil
+}
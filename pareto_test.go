@@ -0,0 +1,56 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"testing"
+)
+
+func TestParetoRoutes(t *testing.T) {
+	fmt.Println("Testing Pareto-optimal routes")
+	g := graph.NewGraph()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		g.InsertVertex(key)
+	}
+	// weights are distance, stops and fare
+	g.InsertArcWithWeights("a", "b", []float64{2, 1, 5})
+	g.InsertArcWithWeights("b", "d", []float64{2, 1, 5})
+	g.InsertArcWithWeights("a", "c", []float64{3, 1, 1})
+	g.InsertArcWithWeights("c", "d", []float64{3, 1, 1})
+	g.InsertArcWithWeights("a", "d", []float64{7, 1, 12})
+	g.InsertArcWithWeights("c", "b", []float64{1, 1, 9})
+
+	result, err := g.FindParetoRoutes("a", "d", 0)
+	fmt.Println(result)
+	if err != nil {
+		t.Errorf("Error should be NIL")
+	}
+	expected := []graph.ParetoRoute{
+		{Route: []string{"a", "b", "d"}, Costs: []float64{4, 2, 10}},
+		{Route: []string{"a", "c", "d"}, Costs: []float64{6, 2, 2}},
+		{Route: []string{"a", "d"}, Costs: []float64{7, 1, 12}}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Solution is not correct")
+	}
+
+	result, _ = g.FindParetoRoutes("a", "d", 2)
+	if len(result) != 2 {
+		t.Errorf("The front should be capped to 2 routes")
+	}
+}
+
+func TestParetoRoutesWithPlainWeights(t *testing.T) {
+	fmt.Println("Testing Pareto-optimal routes with one weight")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	result, err := g.FindParetoRoutes("a", "c", 0)
+	if err != nil || len(result) != 1 || !reflect.DeepEqual(result[0].Route, []string{"a", "b", "c"}) {
+		t.Errorf("With one criterion the front should be the shortest route")
+	}
+	g.InsertArcWithWeights("a", "c", []float64{1, 2})
+	if _, err := g.FindParetoRoutes("a", "c", 0); err == nil {
+		t.Errorf("Error should NOT be NIL")
+	}
+}
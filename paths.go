@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"errors"
+	"iter"
+)
+
+// PathFilter restricts the paths returned by SimplePaths. A zero value means no restriction.
+type PathFilter struct {
+	MaxHops   int      // max number of arcs in a path
+	MaxWeight float64  // max total weight of a path
+	AvoidKeys []string // vertices that must not be used
+	AvoidArcs []Edge   // arcs that must not be used. Only FromKey and ToKey are compared.
+}
+
+// Return every simple path from the vertex 'fromKey' to the vertex 'toKey' that passes the filter, together with
+// its weight. Paths never repeat a vertex and are returned lazily in lexicographic order of their vertex keys.
+// Parallel arcs are considered once, using the cheapest of them.
+func (graph *Graph) SimplePaths(fromKey, toKey string, filter PathFilter) (iter.Seq2[[]string, float64], error) {
+	if graph.First == nil {
+		return nil, errors.New("Graph is empty")
+	}
+	from := graph.findVertex(fromKey)
+	if from == nil {
+		return nil, errors.New("FromKey not found")
+	}
+	to := graph.findVertex(toKey)
+	if to == nil {
+		return nil, errors.New("ToKey not found")
+	}
+	avoidVertex := make(map[string]bool)
+	for _, key := range filter.AvoidKeys {
+		avoidVertex[key] = true
+	}
+	avoidArc := make(map[Edge]bool)
+	for _, arc := range filter.AvoidArcs {
+		avoidArc[Edge{FromKey: arc.FromKey, ToKey: arc.ToKey}] = true
+	}
+
+	return func(yield func([]string, float64) bool) {
+		if avoidVertex[fromKey] || avoidVertex[toKey] {
+			return
+		}
+		if from == to {
+			yield([]string{fromKey}, 0)
+			return
+		}
+		vertices, index := graph.indexVertices()
+		adjacent, weights := cheapestNeighbours(vertices, index)
+		negative := false
+		for _, row := range weights {
+			for _, w := range row {
+				if w < 0 {
+					negative = true
+				}
+			}
+		}
+		onPath := make([]bool, len(vertices))
+		var path []string
+		target := index[to]
+
+		// depth-first search in the order of the arc lists. Return false when the caller stops the iteration.
+		var search func(v int, weight float64) bool
+		search = func(v int, weight float64) bool {
+			onPath[v] = true
+			path = append(path, vertices[v].Key)
+			defer func() {
+				onPath[v] = false
+				path = path[:len(path)-1]
+			}()
+			for k, w := range adjacent[v] {
+				if onPath[w] || avoidVertex[vertices[w].Key] || avoidArc[Edge{FromKey: vertices[v].Key, ToKey: vertices[w].Key}] {
+					continue
+				}
+				total := weight + weights[v][k]
+				if filter.MaxWeight > 0 && total > filter.MaxWeight && !negative {
+					continue
+				}
+				if w == target {
+					if filter.MaxWeight > 0 && total > filter.MaxWeight {
+						continue
+					}
+					solution := make([]string, len(path), len(path)+1)
+					copy(solution, path)
+					if !yield(append(solution, vertices[w].Key), total) {
+						return false
+					}
+					continue
+				}
+				if filter.MaxHops > 0 && len(path) >= filter.MaxHops {
+					continue
+				}
+				if !search(w, total) {
+					return false
+				}
+			}
+			return true
+		}
+		search(index[from], 0)
+	}, nil
+}
@@ -0,0 +1,59 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"testing"
+)
+
+func collectPaths(t *testing.T, g *graph.Graph, from, to string, filter graph.PathFilter) ([][]string, []float64) {
+	paths, err := g.SimplePaths(from, to, filter)
+	if err != nil {
+		t.Errorf("Error should be NIL")
+		return nil, nil
+	}
+	var result [][]string
+	var weights []float64
+	for path, weight := range paths {
+		result = append(result, path)
+		weights = append(weights, weight)
+	}
+	return result, weights
+}
+
+func TestSimplePaths(t *testing.T) {
+	fmt.Println("Testing simple paths")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	result, weights := collectPaths(t, g, "a", "c", graph.PathFilter{})
+	fmt.Println(result, weights)
+	expected := [][]string{
+		{"a", "b", "c"},
+		{"a", "d", "c"},
+		{"a", "d", "e", "b", "c"},
+		{"a", "e", "b", "c"}}
+	if !reflect.DeepEqual(result, expected) || !reflect.DeepEqual(weights, []float64{9, 13, 18, 14}) {
+		t.Errorf("Solution is not correct")
+	}
+}
+
+func TestSimplePathsWithFilter(t *testing.T) {
+	fmt.Println("Testing simple paths with a filter")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	result, _ := collectPaths(t, g, "a", "c", graph.PathFilter{MaxHops: 3, MaxWeight: 13})
+	if !reflect.DeepEqual(result, [][]string{{"a", "b", "c"}, {"a", "d", "c"}}) {
+		t.Errorf("Paths should have at most 3 arcs and weigh at most 13")
+	}
+	result, _ = collectPaths(t, g, "a", "c", graph.PathFilter{AvoidKeys: []string{"d"}, AvoidArcs: []graph.Edge{{FromKey: "a", ToKey: "b"}}})
+	if !reflect.DeepEqual(result, [][]string{{"a", "e", "b", "c"}}) {
+		t.Errorf("Paths should avoid d and the arc a-b")
+	}
+	_, err := g.SimplePaths("a", "x", graph.PathFilter{})
+	if err == nil {
+		t.Errorf("Error should NOT be NIL")
+	}
+}
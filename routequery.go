@@ -0,0 +1,198 @@
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+// Unordered waypoints are solved with a table of 2^n entries, so their number is limited.
+const maxUnorderedWaypoints = 16
+
+// InfeasibleRouteError is returned by RouteQuery.Find when no route satisfies the query.
+type InfeasibleRouteError struct {
+	FromKey string // the part of the route that cannot be completed
+	ToKey   string
+	Reason  string
+}
+
+func (err *InfeasibleRouteError) Error() string {
+	if err.Reason != "" {
+		return "No feasible route: " + err.Reason
+	}
+	return "No feasible route from " + err.FromKey + " to " + err.ToKey
+}
+
+// RouteQuery builds a shortest route query with required waypoints and closures.
+// It is created with NewRouteQuery and run with Find.
+type RouteQuery struct {
+	graph     *Graph
+	fromKey   string
+	toKey     string
+	waypoints []string
+	ordered   bool // waypoints were given with Via
+	unordered bool // waypoints were given with ViaAnyOrder
+	avoidKeys map[string]bool
+	avoidArcs map[Edge]bool
+	filters   []func(from *Vertex, arc *Arc) bool
+}
+
+// Create a query for the cheapest route from the vertex 'fromKey' to the vertex 'toKey'.
+func (graph *Graph) NewRouteQuery(fromKey, toKey string) *RouteQuery {
+	return &RouteQuery{graph: graph, fromKey: fromKey, toKey: toKey,
+		avoidKeys: make(map[string]bool), avoidArcs: make(map[Edge]bool)}
+}
+
+// Require the route to pass through the vertices 'keys' in the given order. Waypoints in order cannot be
+// mixed with waypoints in any order.
+func (query *RouteQuery) Via(keys ...string) *RouteQuery {
+	query.waypoints = append(query.waypoints, keys...)
+	query.ordered = true
+	return query
+}
+
+// Require the route to pass through the vertices 'keys' in any order. Waypoints in any order cannot be
+// mixed with waypoints in order.
+func (query *RouteQuery) ViaAnyOrder(keys ...string) *RouteQuery {
+	query.waypoints = append(query.waypoints, keys...)
+	query.unordered = true
+	return query
+}
+
+// Forbid the route to use the vertices 'keys'.
+func (query *RouteQuery) Avoid(keys ...string) *RouteQuery {
+	for _, key := range keys {
+		query.avoidKeys[key] = true
+	}
+	return query
+}
+
+// Forbid the route to use the arcs from the vertex 'fromKey' to the vertex 'toKey'.
+func (query *RouteQuery) AvoidArc(fromKey, toKey string) *RouteQuery {
+	query.avoidArcs[Edge{FromKey: fromKey, ToKey: toKey}] = true
+	return query
+}
+
+// Only use the arcs accepted by 'filter'. Several filters can be given; an arc must pass all of them.
+func (query *RouteQuery) Filter(filter func(from *Vertex, arc *Arc) bool) *RouteQuery {
+	query.filters = append(query.filters, filter)
+	return query
+}
+
+// Check whether the arc 'arc' leaving the vertex 'from' can be used.
+func (query *RouteQuery) allow(from *Vertex, arc *Arc) bool {
+	if query.avoidKeys[from.Key] || query.avoidKeys[arc.Dest.Key] {
+		return false
+	}
+	if query.avoidArcs[Edge{FromKey: from.Key, ToKey: arc.Dest.Key}] {
+		return false
+	}
+	for _, filter := range query.filters {
+		if !filter(from, arc) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find the cheapest route satisfying the query. The route may pass through a vertex more than once.
+// An *InfeasibleRouteError is returned when no route satisfies the query.
+func (query *RouteQuery) Find() ([]string, float64, error) {
+	if query.ordered && query.unordered {
+		return nil, 0, errors.New("Waypoints in order and in any order cannot be mixed")
+	}
+	stops := append(append([]string{query.fromKey}, query.waypoints...), query.toKey)
+	for _, key := range stops {
+		if query.graph.findVertex(key) == nil {
+			return nil, 0, errors.New("Vertex " + key + " not found")
+		}
+		if query.avoidKeys[key] {
+			return nil, 0, &InfeasibleRouteError{Reason: "vertex " + key + " is both required and avoided"}
+		}
+	}
+	if query.unordered && len(query.waypoints) > maxUnorderedWaypoints {
+		return nil, 0, errors.New("Too many unordered waypoints")
+	}
+	m, err := query.graph.closure(stops, query.allow)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	order := make([]int, len(stops))
+	for i := range order {
+		order[i] = i
+	}
+	if query.unordered && len(query.waypoints) > 1 {
+		if order = m.cheapestOpenPath(); order == nil {
+			return nil, 0, &InfeasibleRouteError{FromKey: query.fromKey, ToKey: query.toKey,
+				Reason: "the waypoints cannot be visited in any order"}
+		}
+	}
+	route := []string{query.fromKey}
+	weight := 0.0
+	for i := 0; i+1 < len(order); i++ {
+		a, b := order[i], order[i+1]
+		if math.IsInf(m.dist[a][b], 1) {
+			return nil, 0, &InfeasibleRouteError{FromKey: stops[a], ToKey: stops[b]}
+		}
+		route = append(route, m.routes[a][b][1:]...)
+		weight += m.dist[a][b]
+	}
+	return route, weight, nil
+}
+
+// Return the cheapest order to visit every station, starting at the first station and ending at the last one,
+// or nil when there is none.
+func (m *metricClosure) cheapestOpenPath() []int {
+	n := len(m.keys)
+	inner := n - 2 // stations between the first and the last one
+	size := 1 << uint(inner)
+	cost := make([][]float64, size)
+	prev := make([][]int, size)
+	for set := range cost {
+		cost[set] = make([]float64, inner)
+		prev[set] = make([]int, inner)
+		for j := range cost[set] {
+			cost[set][j] = math.Inf(1)
+		}
+	}
+	for j := 0; j < inner; j++ {
+		cost[1<<uint(j)][j] = m.dist[0][j+1]
+	}
+	for set := 1; set < size; set++ {
+		for j := 0; j < inner; j++ {
+			if set&(1<<uint(j)) == 0 || math.IsInf(cost[set][j], 1) {
+				continue
+			}
+			for k := 0; k < inner; k++ {
+				if set&(1<<uint(k)) != 0 {
+					continue
+				}
+				next := set | 1<<uint(k)
+				if c := cost[set][j] + m.dist[j+1][k+1]; c < cost[next][k] {
+					cost[next][k] = c
+					prev[next][k] = j
+				}
+			}
+		}
+	}
+	full := size - 1
+	last := -1
+	best := math.Inf(1)
+	for j := 0; j < inner; j++ {
+		if c := cost[full][j] + m.dist[j+1][n-1]; c < best {
+			best = c
+			last = j
+		}
+	}
+	if last == -1 {
+		return nil
+	}
+	order := make([]int, n)
+	order[n-1] = n - 1
+	set := full
+	for i := n - 2; i > 0; i-- {
+		order[i] = last + 1
+		last, set = prev[set][last], set&^(1<<uint(last))
+	}
+	return order
+}
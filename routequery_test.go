@@ -0,0 +1,58 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"testing"
+)
+
+func TestRouteQueryWaypoints(t *testing.T) {
+	fmt.Println("Testing route query with waypoints")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	route, weight, err := g.NewRouteQuery("a", "c").Via("e").Find()
+	fmt.Println(route, weight)
+	if err != nil || !reflect.DeepEqual(route, []string{"a", "e", "b", "c"}) || weight != 14 {
+		t.Errorf("Solution is not correct")
+	}
+	route, weight, err = g.NewRouteQuery("a", "e").ViaAnyOrder("b", "d").Find()
+	fmt.Println(route, weight)
+	if err != nil || !reflect.DeepEqual(route, []string{"a", "d", "e", "b", "c", "e"}) || weight != 20 {
+		t.Errorf("Solution is not correct")
+	}
+	_, _, err = g.NewRouteQuery("a", "e").Via("c").ViaAnyOrder("b", "d").Find()
+	if err == nil {
+		t.Errorf("Error should NOT be NIL when waypoints in order and in any order are mixed")
+	}
+}
+
+func TestRouteQueryClosures(t *testing.T) {
+	fmt.Println("Testing route query with closures")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	route, weight, err := g.NewRouteQuery("a", "c").Avoid("b").Find()
+	if err != nil || !reflect.DeepEqual(route, []string{"a", "d", "c"}) || weight != 13 {
+		t.Errorf("The route should avoid b")
+	}
+	route, _, err = g.NewRouteQuery("a", "c").AvoidArc("a", "b").AvoidArc("a", "d").Find()
+	if err != nil || !reflect.DeepEqual(route, []string{"a", "e", "b", "c"}) {
+		t.Errorf("The route should avoid the arcs a-b and a-d")
+	}
+	route, _, err = g.NewRouteQuery("a", "c").Filter(func(from *graph.Vertex, arc *graph.Arc) bool {
+		return arc.Weight > 4
+	}).Find()
+	if err != nil || !reflect.DeepEqual(route, []string{"a", "d", "c"}) {
+		t.Errorf("The route should only use arcs heavier than 4")
+	}
+	_, _, err = g.NewRouteQuery("a", "c").Avoid("b", "d").Find()
+	if _, ok := err.(*graph.InfeasibleRouteError); !ok {
+		t.Errorf("Error should be an InfeasibleRouteError")
+	}
+	_, _, err = g.NewRouteQuery("a", "c").Via("d").Avoid("d").Find()
+	if _, ok := err.(*graph.InfeasibleRouteError); !ok {
+		t.Errorf("Error should be an InfeasibleRouteError")
+	}
+}
@@ -0,0 +1,226 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RouteSpecError reports where a route spec is malformed. Lines and columns start at 1; columns count characters.
+type RouteSpecError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (err *RouteSpecError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", err.Line, err.Column, err.Message)
+}
+
+// A reader of a route spec that keeps track of the position.
+type routeSpecScanner struct {
+	runes  []rune
+	pos    int
+	line   int
+	column int
+}
+
+func (s *routeSpecScanner) peek() rune {
+	if s.pos < len(s.runes) {
+		return s.runes[s.pos]
+	}
+	return -1
+}
+
+func (s *routeSpecScanner) next() rune {
+	r := s.peek()
+	if r == -1 {
+		return r
+	}
+	s.pos++
+	if r == '\n' {
+		s.line++
+		s.column = 1
+	} else {
+		s.column++
+	}
+	return r
+}
+
+func (s *routeSpecScanner) errorf(line, column int, format string, args ...interface{}) error {
+	return &RouteSpecError{Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+// Skip spaces, commas and comments. Return whether a comma or a line break was skipped.
+func (s *routeSpecScanner) skipSeparators() bool {
+	separated := false
+	for {
+		switch r := s.peek(); {
+		case r == ',' || r == '\n':
+			separated = true
+			s.next()
+		case r == '#':
+			for s.peek() != '\n' && s.peek() != -1 {
+				s.next()
+			}
+		case r != -1 && unicode.IsSpace(r):
+			s.next()
+		default:
+			return separated
+		}
+	}
+}
+
+// Check whether a character may be used as a key of one character without brackets.
+func isBareKey(r rune) bool {
+	return r != -1 && !unicode.IsSpace(r) && !unicode.IsDigit(r) && !strings.ContainsRune(",#[]\\.-+", r)
+}
+
+// Read a key: one character, or any text in brackets where '\' escapes the next character.
+func (s *routeSpecScanner) key() (string, error) {
+	line, column := s.line, s.column
+	r := s.peek()
+	if r == '[' {
+		s.next()
+		var key strings.Builder
+		for {
+			r := s.next()
+			switch r {
+			case -1, '\n':
+				return "", s.errorf(line, column, "unterminated key")
+			case ']':
+				if key.Len() == 0 {
+					return "", s.errorf(line, column, "empty key")
+				}
+				return key.String(), nil
+			case '\\':
+				escaped := s.next()
+				if escaped == -1 || escaped == '\n' {
+					return "", s.errorf(line, column, "unterminated key")
+				}
+				key.WriteRune(escaped)
+			default:
+				key.WriteRune(r)
+			}
+		}
+	}
+	if !isBareKey(r) {
+		if r == -1 {
+			return "", s.errorf(line, column, "expected a key, found the end of the spec")
+		}
+		return "", s.errorf(line, column, "expected a key, found %q", r)
+	}
+	s.next()
+	return string(r), nil
+}
+
+// Read a weight: a decimal number with an optional sign and fraction.
+func (s *routeSpecScanner) weight() (float64, error) {
+	line, column := s.line, s.column
+	var text strings.Builder
+	if r := s.peek(); r == '-' || r == '+' {
+		text.WriteRune(s.next())
+	}
+	digits := 0
+	for unicode.IsDigit(s.peek()) || s.peek() == '.' {
+		if s.peek() != '.' {
+			digits++
+		}
+		text.WriteRune(s.next())
+	}
+	if digits == 0 {
+		if r := s.peek(); r == -1 || r == ',' || r == '\n' {
+			return 0, s.errorf(line, column, "missing weight")
+		}
+		return 0, s.errorf(line, column, "expected a weight, found %q", s.peek())
+	}
+	weight, err := strconv.ParseFloat(text.String(), 64)
+	if err != nil {
+		return 0, s.errorf(line, column, "invalid weight %q", text.String())
+	}
+	return weight, nil
+}
+
+// Build a graph from a route spec such as "AB5, BC4, CD8". Every token is an arc: the key of its origin, the key
+// of its destination and its weight. A key is one character, or any text in brackets such as "[Central]"; a '\'
+// in brackets escapes the next character. Weights are decimal numbers such as "2.5" or "-1". A token with a single
+// key is a vertex without arcs. Tokens are separated by commas or line breaks, and '#' starts a comment running
+// to the end of the line. Errors are reported with a *RouteSpecError.
+func ParseRouteSpec(spec string) (*Graph, error) {
+	graph := NewGraph()
+	s := &routeSpecScanner{runes: []rune(spec), line: 1, column: 1}
+	s.skipSeparators()
+	for s.peek() != -1 {
+		line, column := s.line, s.column
+		fromKey, err := s.key()
+		if err != nil {
+			return nil, err
+		}
+		if graph.findVertex(fromKey) == nil {
+			graph.InsertVertex(fromKey)
+		}
+		for s.peek() == ' ' || s.peek() == '\t' {
+			s.next()
+		}
+		if s.skipSeparators() || s.peek() == -1 {
+			continue // a vertex without arcs
+		}
+		toKey, err := s.key()
+		if err != nil {
+			return nil, err
+		}
+		for s.peek() == ' ' || s.peek() == '\t' {
+			s.next()
+		}
+		weight, err := s.weight()
+		if err != nil {
+			return nil, err
+		}
+		if graph.findVertex(toKey) == nil {
+			graph.InsertVertex(toKey)
+		}
+		graph.InsertArc(fromKey, toKey, weight)
+		for s.peek() == ' ' || s.peek() == '\t' {
+			s.next()
+		}
+		if !s.skipSeparators() && s.peek() != -1 {
+			return nil, s.errorf(s.line, s.column, "expected ',' after the token at %d:%d, found %q", line, column, s.peek())
+		}
+	}
+	return graph, nil
+}
+
+// Return a key as it is written in a route spec.
+func formatRouteSpecKey(key string) string {
+	if r := []rune(key); len(r) == 1 && isBareKey(r[0]) {
+		return key
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `]`, `\]`)
+	return "[" + replacer.Replace(key) + "]"
+}
+
+// Write the graph as a route spec that ParseRouteSpec reads back into the same graph. Arcs are written in the
+// order of the vertex list, and vertices without arcs as tokens of a single key. Only keys and weights are kept.
+// Keys must not contain line breaks and weights must be finite.
+func (graph *Graph) FormatRouteSpec() (string, error) {
+	var tokens []string
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		if strings.Contains(vPtr.Key, "\n") || vPtr.Key == "" {
+			return "", errors.New("Key " + strconv.Quote(vPtr.Key) + " cannot be written in a route spec")
+		}
+		if vPtr.Arc == nil && vPtr.InDegree == 0 {
+			tokens = append(tokens, formatRouteSpecKey(vPtr.Key))
+			continue
+		}
+		for _, aPtr := range vPtr.arcsInInsertionOrder() {
+			if math.IsInf(aPtr.Weight, 0) || math.IsNaN(aPtr.Weight) {
+				return "", errors.New("Weight of the arc " + vPtr.Key + "-" + aPtr.Dest.Key + " is not finite")
+			}
+			tokens = append(tokens, formatRouteSpecKey(vPtr.Key)+formatRouteSpecKey(aPtr.Dest.Key)+strconv.FormatFloat(aPtr.Weight, 'f', -1, 64))
+		}
+	}
+	return strings.Join(tokens, ", "), nil
+}
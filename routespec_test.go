@@ -0,0 +1,80 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"testing"
+)
+
+func TestParseRouteSpec(t *testing.T) {
+	fmt.Println("Testing route spec parsing")
+	g, err := graph.ParseRouteSpec("ab5, bc4, cd8, dc8, de6, ad5, ce2, eb3, ae7")
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	expected := graph.NewGraph()
+	initGraph(expected)
+	if !reflect.DeepEqual(g, expected) {
+		t.Errorf("The spec should build the same graph as initGraph")
+	}
+
+	g, err = graph.ParseRouteSpec("# stations\n[Central Station][Airport \\] T1]12.5,\n[Airport \\] T1] Z -1.25\nY")
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	distance, err := g.FindDistance([]string{"Central Station", "Airport ] T1", "Z"})
+	if err != nil || distance != 11.25 || g.Count != 4 {
+		t.Errorf("Keys in brackets and decimal weights should be parsed")
+	}
+}
+
+func TestParseRouteSpecErrors(t *testing.T) {
+	fmt.Println("Testing route spec errors")
+	cases := []struct {
+		spec         string
+		line, column int
+	}{
+		{"AB5, BC", 1, 8},
+		{"AB5,\nB9C", 2, 2},
+		{"AB5\n  [Long", 2, 3},
+		{"AB5 BC4", 1, 5},
+		{"AB1.2.3", 1, 3},
+	}
+	for _, c := range cases {
+		_, err := graph.ParseRouteSpec(c.spec)
+		specErr, ok := err.(*graph.RouteSpecError)
+		if !ok {
+			t.Errorf("Error of %q should be a RouteSpecError", c.spec)
+			continue
+		}
+		if specErr.Line != c.line || specErr.Column != c.column {
+			t.Errorf("Error of %q should be at %d:%d, not %v", c.spec, c.line, c.column, err)
+		}
+	}
+}
+
+func TestFormatRouteSpec(t *testing.T) {
+	fmt.Println("Testing route spec round trips")
+	g := graph.NewGraph()
+	initGraph(g)
+	g.InsertVertex("Lonely Town")
+	g.InsertVertex("[x]")
+	g.InsertArc("a", "[x]", 0.5)
+	g.InsertArc("a", "b", 9)
+	spec, err := g.FormatRouteSpec()
+	if err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	expected := `[Lonely Town], a[[x\]]0.5, ab5, ab9, ad5, ae7, bc4, cd8, ce2, dc8, de6, eb3`
+	if spec != expected {
+		t.Errorf("The spec should be %s, not %s", expected, spec)
+	}
+	parsed, err := graph.ParseRouteSpec(spec)
+	if err != nil || !reflect.DeepEqual(parsed, g) {
+		t.Errorf("The spec should be parsed into the same graph: %s", spec)
+	}
+}
@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+// Semiring is a path algebra. The value of a path is the Extend of its arc weights, starting from One, and
+// the value between two vertices is the Combine of the values of all paths, starting from Zero.
+type Semiring interface {
+	Combine(a, b float64) float64 // combine the values of two alternative paths
+	Extend(a, b float64) float64  // extend the value of a path by the weight of an arc
+	Zero() float64                // value when there is no path; the identity of Combine
+	One() float64                 // value of the empty path; the identity of Extend
+}
+
+// ShortestPath is the (min, +) semiring: the value of a path is its total weight. Zero is math.MaxFloat64,
+// the INFINITY used by FindShortestRoute.
+type ShortestPath struct{}
+
+func (ShortestPath) Combine(a, b float64) float64 { return math.Min(a, b) }
+func (ShortestPath) Extend(a, b float64) float64  { return a + b }
+func (ShortestPath) Zero() float64                { return math.MaxFloat64 }
+func (ShortestPath) One() float64                 { return 0 }
+
+// WidestPath is the (max, min) semiring: the value of a path is its lightest arc weight.
+type WidestPath struct{}
+
+func (WidestPath) Combine(a, b float64) float64 { return math.Max(a, b) }
+func (WidestPath) Extend(a, b float64) float64  { return math.Min(a, b) }
+func (WidestPath) Zero() float64                { return math.Inf(-1) }
+func (WidestPath) One() float64                 { return math.Inf(1) }
+
+// ReliablePath is the (max, *) semiring: the value of a path is the product of its arc weights,
+// which must be probabilities between 0 and 1.
+type ReliablePath struct{}
+
+func (ReliablePath) Combine(a, b float64) float64 { return math.Max(a, b) }
+func (ReliablePath) Extend(a, b float64) float64  { return a * b }
+func (ReliablePath) Zero() float64                { return 0 }
+func (ReliablePath) One() float64                 { return 1 }
+
+// PathCount is the (+, *) semiring: every path counts the product of its arc weights, so that the value between
+// two vertices is the number of paths when every weight is 1. It is only meaningful for graphs without cycles and
+// cannot be used with FindBestRoute.
+type PathCount struct{}
+
+func (PathCount) Combine(a, b float64) float64 { return a + b }
+func (PathCount) Extend(a, b float64) float64  { return a * b }
+func (PathCount) Zero() float64                { return 0 }
+func (PathCount) One() float64                 { return 1 }
+
+// Check whether the value 'a' is strictly better than the value 'b' in a selective semiring.
+func better(semiring Semiring, a, b float64) bool {
+	return a != b && semiring.Combine(a, b) == a
+}
+
+// PathMatrix holds the value of the paths between every pair of vertices.
+type PathMatrix struct {
+	Keys   []string    // keys of the vertices in the order of the vertex list
+	Values [][]float64 // Values[i][j] is the value from Keys[i] to Keys[j]
+}
+
+// Return the value of the paths from the vertex 'fromKey' to the vertex 'toKey'.
+func (matrix *PathMatrix) Get(fromKey, toKey string) (float64, error) {
+	from, to := -1, -1
+	for i, key := range matrix.Keys {
+		if key == fromKey {
+			from = i
+		}
+		if key == toKey {
+			to = i
+		}
+	}
+	if from == -1 {
+		return 0, errors.New("FromKey not found")
+	}
+	if to == -1 {
+		return 0, errors.New("ToKey not found")
+	}
+	return matrix.Values[from][to], nil
+}
+
+// Compute the value of the paths between every pair of vertices under the path algebra 'semiring'
+// with the Floyd-Warshall algorithm. The value from a vertex to itself includes the empty path.
+func (graph *Graph) FindAllPairs(semiring Semiring) *PathMatrix {
+	vertices, index := graph.indexVertices()
+	n := len(vertices)
+	matrix := &PathMatrix{Keys: make([]string, n), Values: make([][]float64, n)}
+	for i, v := range vertices {
+		matrix.Keys[i] = v.Key
+		matrix.Values[i] = make([]float64, n)
+		for j := range matrix.Values[i] {
+			matrix.Values[i][j] = semiring.Zero()
+		}
+	}
+	values := matrix.Values
+	for i, v := range vertices {
+		for aPtr := v.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			j := index[aPtr.Dest]
+			values[i][j] = semiring.Combine(values[i][j], aPtr.Weight)
+		}
+	}
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			if values[i][k] == semiring.Zero() {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if values[k][j] == semiring.Zero() {
+					continue
+				}
+				values[i][j] = semiring.Combine(values[i][j], semiring.Extend(values[i][k], values[k][j]))
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		values[i][i] = semiring.Combine(semiring.One(), values[i][i])
+	}
+	return matrix
+}
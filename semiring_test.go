@@ -0,0 +1,67 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// MinMaxArc is the (min, max) semiring: the best route has the lightest heaviest arc.
+type MinMaxArc struct{}
+
+func (MinMaxArc) Combine(a, b float64) float64 { return math.Min(a, b) }
+func (MinMaxArc) Extend(a, b float64) float64  { return math.Max(a, b) }
+func (MinMaxArc) Zero() float64                { return math.Inf(1) }
+func (MinMaxArc) One() float64                 { return math.Inf(-1) }
+
+func TestBestRouteWithCustomSemiring(t *testing.T) {
+	fmt.Println("Testing best route with a custom semiring")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	route, heaviest, err := g.FindBestRoute("a", "e", MinMaxArc{})
+	if err != nil || !reflect.DeepEqual(route, []string{"a", "b", "c", "e"}) || heaviest != 5 {
+		t.Errorf("Solution is not correct")
+	}
+	route, weight, err := g.FindBestRoute("a", "c", graph.ShortestPath{})
+	if err != nil || !reflect.DeepEqual(route, []string{"a", "b", "c"}) || weight != 9 {
+		t.Errorf("Solution is not correct")
+	}
+}
+
+func TestAllPairs(t *testing.T) {
+	fmt.Println("Testing all-pairs path values")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	shortest := g.FindAllPairs(graph.ShortestPath{})
+	if value, err := shortest.Get("a", "c"); err != nil || value != 9 {
+		t.Errorf("The shortest distance from a to c should be 9")
+	}
+	if value, _ := shortest.Get("c", "a"); value != math.MaxFloat64 {
+		t.Errorf("There should be no path from c to a")
+	}
+	widest := g.FindAllPairs(graph.WidestPath{})
+	if value, _ := widest.Get("a", "c"); value != 5 {
+		t.Errorf("The widest path from a to c should be 5")
+	}
+
+	dag := graph.NewGraph()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		dag.InsertVertex(key)
+	}
+	dag.InsertArc("a", "b", 1)
+	dag.InsertArc("a", "c", 1)
+	dag.InsertArc("b", "d", 1)
+	dag.InsertArc("c", "d", 1)
+	dag.InsertArc("a", "d", 1)
+	count := dag.FindAllPairs(graph.PathCount{})
+	if value, _ := count.Get("a", "d"); value != 3 {
+		t.Errorf("There should be 3 paths from a to d")
+	}
+	if _, err := count.Get("a", "x"); err == nil {
+		t.Errorf("Error should NOT be NIL")
+	}
+}
@@ -0,0 +1,487 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Versions of the snapshot format. Version 1 holds keys and weights; version 2 adds capacities, weight vectors
+// and attributes. ReadSnapshot reads every version up to SnapshotVersion.
+const (
+	SnapshotVersion    = 2
+	minSnapshotVersion = 1
+)
+
+var snapshotMagic = []byte("GSNP")
+
+var snapshotTable = crc32.MakeTable(crc32.Castagnoli)
+
+// SnapshotError reports a snapshot that is truncated or corrupted. Offset is the position in bytes where the
+// problem was found.
+type SnapshotError struct {
+	Offset    int64
+	Truncated bool
+	Message   string
+}
+
+func (err *SnapshotError) Error() string {
+	return "snapshot offset " + strconv.FormatInt(err.Offset, 10) + ": " + err.Message
+}
+
+// SnapshotVersionError reports a snapshot written in a version that this reader does not know.
+type SnapshotVersionError struct {
+	Version   int
+	Supported int // the newest version that can be read
+}
+
+func (err *SnapshotVersionError) Error() string {
+	return fmt.Sprintf("snapshot version %d is not supported; versions %d to %d can be read", err.Version, minSnapshotVersion, err.Supported)
+}
+
+// SnapshotChecksumError reports a snapshot whose content does not match its checksum.
+type SnapshotChecksumError struct {
+	Expected uint32
+	Actual   uint32
+}
+
+func (err *SnapshotChecksumError) Error() string {
+	return fmt.Sprintf("snapshot checksum mismatch: expected %08x, found %08x", err.Expected, err.Actual)
+}
+
+// Flags of an arc in version 2.
+const (
+	snapshotCapacity   = 1 << iota // the arc has a capacity
+	snapshotWeights                // the arc has a weight vector
+	snapshotAttributes             // the arc has attributes
+)
+
+// Kinds of attribute values in version 2.
+var snapshotKinds = []string{attributeString, attributeInt, attributeFloat, attributeBool}
+
+// Builds the payload of a snapshot.
+type snapshotWriter struct {
+	buffer  bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+	strings map[string]int
+}
+
+func (s *snapshotWriter) uvarint(x uint64) {
+	s.buffer.Write(s.scratch[:binary.PutUvarint(s.scratch[:], x)])
+}
+
+func (s *snapshotWriter) float(f float64) {
+	binary.LittleEndian.PutUint64(s.scratch[:8], math.Float64bits(f))
+	s.buffer.Write(s.scratch[:8])
+}
+
+func (s *snapshotWriter) attributes(attributes Attributes) error {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	s.uvarint(uint64(len(names)))
+	for _, name := range names {
+		s.uvarint(uint64(s.strings[name]))
+		switch v := attributes[name].(type) {
+		case string:
+			s.buffer.WriteByte(0)
+			s.uvarint(uint64(s.strings[v]))
+		case int64:
+			s.buffer.WriteByte(1)
+			s.buffer.Write(s.scratch[:binary.PutVarint(s.scratch[:], v)])
+		case float64:
+			s.buffer.WriteByte(2)
+			s.float(v)
+		case bool:
+			s.buffer.WriteByte(3)
+			if v {
+				s.buffer.WriteByte(1)
+			} else {
+				s.buffer.WriteByte(0)
+			}
+		default:
+			return errors.New("Attribute " + name + " is not a string, int64, float64 or bool")
+		}
+	}
+	return nil
+}
+
+// Write the graph as a binary snapshot in the newest version. See WriteSnapshotVersion.
+func (graph *Graph) WriteSnapshot(w io.Writer) error {
+	return graph.WriteSnapshotVersion(w, SnapshotVersion)
+}
+
+// Write the graph as a binary snapshot in the version 'version', so that older readers can load it. Data that the
+// version cannot hold, such as attributes in version 1, is an error rather than being dropped. The layout is
+//
+//	"GSNP" | version (uvarint) | payload length (uvarint) | payload | CRC-32C of everything before (4 bytes)
+//
+// The payload starts with a string table whose first entries are the vertex keys in key order, followed by the
+// arcs of every vertex as the index of their destination (uvarint) and their weight (8 bytes). Version 2 adds flags,
+// capacities, weight vectors and attributes to the arcs, then the attributes of the vertices. Integers are
+// little-endian; travel times are not written.
+func (graph *Graph) WriteSnapshotVersion(w io.Writer, version int) error {
+	if version < minSnapshotVersion || version > SnapshotVersion {
+		return &SnapshotVersionError{Version: version, Supported: SnapshotVersion}
+	}
+	vertices, index := graph.indexVertices()
+	s := &snapshotWriter{strings: make(map[string]int)}
+	table := make([]string, 0, len(vertices))
+	addString := func(text string) {
+		if _, ok := s.strings[text]; !ok {
+			s.strings[text] = len(table)
+			table = append(table, text)
+		}
+	}
+	for _, vPtr := range vertices {
+		s.strings[vPtr.Key] = len(table)
+		table = append(table, vPtr.Key)
+	}
+	addAttributes := func(attributes Attributes) {
+		for name, value := range attributes {
+			addString(name)
+			if text, ok := value.(string); ok {
+				addString(text)
+			}
+		}
+	}
+	arcCount := 0
+	for _, vPtr := range vertices {
+		for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			arcCount++
+			if version == 1 && (aPtr.HasCapacity || aPtr.Weights != nil || aPtr.Attributes != nil) {
+				return errors.New("Arc " + vPtr.Key + "-" + aPtr.Dest.Key + " has data that version 1 cannot hold")
+			}
+			addAttributes(aPtr.Attributes)
+		}
+		if version == 1 && vPtr.Attributes != nil {
+			return errors.New("Vertex " + vPtr.Key + " has attributes that version 1 cannot hold")
+		}
+		addAttributes(vPtr.Attributes)
+	}
+
+	s.uvarint(uint64(len(table)))
+	for _, text := range table {
+		s.uvarint(uint64(len(text)))
+		s.buffer.WriteString(text)
+	}
+	s.uvarint(uint64(len(vertices)))
+	s.uvarint(uint64(arcCount))
+	for _, vPtr := range vertices {
+		s.uvarint(uint64(vPtr.OutDegree))
+		for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			s.uvarint(uint64(index[aPtr.Dest]))
+			s.float(aPtr.Weight)
+			if version == 1 {
+				continue
+			}
+			var flags byte
+			if aPtr.HasCapacity {
+				flags |= snapshotCapacity
+			}
+			if aPtr.Weights != nil {
+				flags |= snapshotWeights
+			}
+			if aPtr.Attributes != nil {
+				flags |= snapshotAttributes
+			}
+			s.buffer.WriteByte(flags)
+			if flags&snapshotCapacity != 0 {
+				s.float(aPtr.Capacity)
+			}
+			if flags&snapshotWeights != 0 {
+				s.uvarint(uint64(len(aPtr.Weights)))
+				for _, weight := range aPtr.Weights {
+					s.float(weight)
+				}
+			}
+			if flags&snapshotAttributes != 0 {
+				if err := s.attributes(aPtr.Attributes); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if version >= 2 {
+		for _, vPtr := range vertices {
+			if vPtr.Attributes == nil {
+				s.buffer.WriteByte(0)
+				continue
+			}
+			s.buffer.WriteByte(1)
+			if err := s.attributes(vPtr.Attributes); err != nil {
+				return err
+			}
+		}
+	}
+
+	header := append([]byte(nil), snapshotMagic...)
+	header = binary.AppendUvarint(header, uint64(version))
+	header = binary.AppendUvarint(header, uint64(s.buffer.Len()))
+	checksum := crc32.Update(crc32.Checksum(header, snapshotTable), snapshotTable, s.buffer.Bytes())
+	for _, part := range [][]byte{header, s.buffer.Bytes(), binary.LittleEndian.AppendUint32(nil, checksum)} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reads the payload of a snapshot. Offsets in errors count from the start of the snapshot.
+type snapshotReader struct {
+	data   []byte
+	pos    int
+	offset int // position of the payload in the snapshot
+	err    error
+}
+
+func (s *snapshotReader) fail(message string) {
+	if s.err == nil {
+		s.err = &SnapshotError{Offset: int64(s.offset + s.pos), Message: message}
+	}
+}
+
+func (s *snapshotReader) uvarint() uint64 {
+	if s.err != nil {
+		return 0
+	}
+	x, n := binary.Uvarint(s.data[s.pos:])
+	if n <= 0 {
+		s.fail("invalid varint")
+		return 0
+	}
+	s.pos += n
+	return x
+}
+
+// Read a count of items that take at least 'size' bytes each, checking it against the bytes left.
+func (s *snapshotReader) count(size int) int {
+	n := s.uvarint()
+	if n > uint64(len(s.data)-s.pos)/uint64(size) {
+		s.fail("count " + strconv.FormatUint(n, 10) + " exceeds the data")
+		return 0
+	}
+	return int(n)
+}
+
+func (s *snapshotReader) bytes(n int) []byte {
+	if s.err != nil {
+		return nil
+	}
+	if n > len(s.data)-s.pos {
+		s.fail("value exceeds the data")
+		return nil
+	}
+	s.pos += n
+	return s.data[s.pos-n : s.pos]
+}
+
+func (s *snapshotReader) float() float64 {
+	if b := s.bytes(8); b != nil {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (s *snapshotReader) byte() byte {
+	if b := s.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+// Read the index of an entry of a table of 'n' entries.
+func (s *snapshotReader) index(n int) int {
+	i := s.uvarint()
+	if s.err == nil && i >= uint64(n) {
+		s.fail("index " + strconv.FormatUint(i, 10) + " out of range")
+		return 0
+	}
+	return int(i)
+}
+
+// Read the index of an entry of the string table and return the entry.
+func (s *snapshotReader) string(table []string) string {
+	if i := s.index(len(table)); s.err == nil {
+		return table[i]
+	}
+	return ""
+}
+
+func (s *snapshotReader) attributes(table []string) Attributes {
+	n := s.count(3)
+	attributes := make(Attributes, n)
+	for i := 0; i < n && s.err == nil; i++ {
+		name := s.string(table)
+		kind := s.byte()
+		if int(kind) >= len(snapshotKinds) {
+			s.fail("unknown attribute kind")
+			return nil
+		}
+		switch snapshotKinds[kind] {
+		case attributeString:
+			attributes[name] = s.string(table)
+		case attributeInt:
+			v, n := binary.Varint(s.data[s.pos:])
+			if n <= 0 {
+				s.fail("invalid varint")
+				return nil
+			}
+			s.pos += n
+			attributes[name] = v
+		case attributeFloat:
+			attributes[name] = s.float()
+		case attributeBool:
+			attributes[name] = s.byte() != 0
+		}
+	}
+	return attributes
+}
+
+// Read a binary snapshot written by WriteSnapshot in any version up to SnapshotVersion. The checksum is verified
+// before anything is decoded, and the vertices and arcs are allocated in blocks and linked directly, without the
+// searches of InsertVertex and InsertArc. Truncated and corrupted snapshots are reported with a *SnapshotError,
+// a *SnapshotChecksumError or a *SnapshotVersionError.
+func ReadSnapshot(r io.Reader) (*Graph, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < len(snapshotMagic) {
+		return nil, &SnapshotError{Offset: int64(len(data)), Truncated: true, Message: "unexpected end of data"}
+	}
+	if !bytes.Equal(data[:len(snapshotMagic)], snapshotMagic) {
+		return nil, &SnapshotError{Offset: 0, Message: "not a graph snapshot"}
+	}
+	pos := len(snapshotMagic)
+	var header [2]uint64
+	for i := range header {
+		x, n := binary.Uvarint(data[pos:])
+		if n == 0 {
+			return nil, &SnapshotError{Offset: int64(len(data)), Truncated: true, Message: "unexpected end of data"}
+		}
+		if n < 0 {
+			return nil, &SnapshotError{Offset: int64(pos), Message: "invalid varint"}
+		}
+		header[i], pos = x, pos+n
+		if i == 0 && (x < minSnapshotVersion || x > SnapshotVersion) {
+			return nil, &SnapshotVersionError{Version: int(min(x, math.MaxInt32)), Supported: SnapshotVersion}
+		}
+	}
+	version, length := int(header[0]), header[1]
+	if length > uint64(len(data)-pos) || uint64(len(data)-pos)-length < 4 {
+		return nil, &SnapshotError{Offset: int64(len(data)), Truncated: true, Message: "unexpected end of data"}
+	}
+	end := pos + int(length)
+	if end+4 != len(data) {
+		return nil, &SnapshotError{Offset: int64(end + 4), Message: "unexpected data after the snapshot"}
+	}
+	expected := binary.LittleEndian.Uint32(data[end:])
+	if actual := crc32.Checksum(data[:end], snapshotTable); actual != expected {
+		return nil, &SnapshotChecksumError{Expected: expected, Actual: actual}
+	}
+
+	s := &snapshotReader{data: data[pos:end], offset: pos}
+	table := make([]string, s.count(1))
+	for i := range table {
+		table[i] = string(s.bytes(s.count(1)))
+	}
+	n := s.count(1)
+	if n > len(table) {
+		s.fail("more vertices than strings")
+	}
+	m := s.count(9)
+	if s.err != nil {
+		return nil, s.err
+	}
+	graph := NewGraph()
+	vertices := make([]Vertex, n)
+	arcs := make([]Arc, m)
+	for i := range vertices {
+		vertices[i].Key = table[i]
+		if i > 0 {
+			if vertices[i-1].Key >= vertices[i].Key {
+				s.fail("vertex keys out of order")
+				return nil, s.err
+			}
+			vertices[i-1].NextVertex = &vertices[i]
+		}
+	}
+	if n > 0 {
+		graph.First = &vertices[0]
+	}
+	graph.Count = n
+	used := 0
+	for i := range vertices {
+		vPtr := &vertices[i]
+		degree := s.count(9)
+		if degree > m-used {
+			s.fail("more arcs than declared")
+		}
+		var last *Arc
+		for k := 0; k < degree && s.err == nil; k++ {
+			aPtr := &arcs[used]
+			used++
+			aPtr.Dest = &vertices[s.index(n)]
+			aPtr.Weight = s.float()
+			if last != nil && last.Dest.Key > aPtr.Dest.Key {
+				s.fail("arcs out of order")
+			}
+			if version >= 2 {
+				flags := s.byte()
+				if flags&snapshotCapacity != 0 {
+					aPtr.Capacity, aPtr.HasCapacity = s.float(), true
+				}
+				if flags&snapshotWeights != 0 {
+					aPtr.Weights = make([]float64, s.count(8))
+					for w := range aPtr.Weights {
+						aPtr.Weights[w] = s.float()
+					}
+				}
+				if flags&snapshotAttributes != 0 {
+					aPtr.Attributes = s.attributes(table)
+				}
+			}
+			if last == nil {
+				vPtr.Arc = aPtr
+			} else {
+				last.NextArc = aPtr
+			}
+			last = aPtr
+			vPtr.OutDegree++
+			aPtr.Dest.InDegree++
+		}
+		if s.err != nil {
+			return nil, s.err
+		}
+	}
+	if used != m {
+		s.fail("fewer arcs than declared")
+	}
+	if version >= 2 {
+		for i := range vertices {
+			switch s.byte() {
+			case 0:
+			case 1:
+				vertices[i].Attributes = s.attributes(table)
+			default:
+				s.fail("invalid attribute flag")
+			}
+		}
+	}
+	if s.err == nil && s.pos != len(s.data) {
+		s.fail("unexpected data after the graph")
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return graph, nil
+}
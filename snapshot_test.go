@@ -0,0 +1,101 @@
+package graph_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/audathuynh/graph"
+	"hash/crc32"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	fmt.Println("Testing binary snapshots")
+	g := attributedGraph()
+	g.InsertArc("a", "b", math.Inf(1))
+	g.InsertArcWithCapacity("e", "a", 4, 1.5)
+	g.InsertArcWithCapacity("d", "a", 0, 2) // a capacity of 0 is kept
+	g.InsertArcWithWeights("c", "a", []float64{1, 2, 3})
+	var out bytes.Buffer
+	if err := g.WriteSnapshot(&out); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	parsed, err := graph.ReadSnapshot(bytes.NewReader(out.Bytes()))
+	if err != nil || !reflect.DeepEqual(parsed, g) {
+		t.Errorf("The snapshot should be read back into the same graph: %v", err)
+	}
+
+	if err := g.WriteSnapshotVersion(&out, 1); err == nil {
+		t.Errorf("Version 1 cannot hold attributes")
+	}
+	plain := graph.NewGraph()
+	initGraph(plain)
+	out.Reset()
+	if err := plain.WriteSnapshotVersion(&out, 1); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+	}
+	parsed, err = graph.ReadSnapshot(&out)
+	if err != nil || !reflect.DeepEqual(parsed, plain) {
+		t.Errorf("A version 1 snapshot should be read: %v", err)
+	}
+	var empty bytes.Buffer
+	graph.NewGraph().WriteSnapshot(&empty)
+	if parsed, err := graph.ReadSnapshot(&empty); err != nil || parsed.First != nil || parsed.Count != 0 {
+		t.Errorf("An empty graph should be read: %v", err)
+	}
+}
+
+func TestSnapshotErrors(t *testing.T) {
+	fmt.Println("Testing corrupted snapshots")
+	g := graph.NewGraph()
+	initGraph(g)
+	var out bytes.Buffer
+	g.WriteSnapshot(&out)
+	data := out.Bytes()
+
+	for n := 0; n < len(data); n++ {
+		_, err := graph.ReadSnapshot(bytes.NewReader(data[:n]))
+		if snapshotErr, ok := err.(*graph.SnapshotError); !ok || !snapshotErr.Truncated {
+			t.Errorf("A snapshot cut at %d bytes should be truncated: %v", n, err)
+		}
+	}
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[len(corrupted)/2] ^= 0x10
+	if _, err := graph.ReadSnapshot(bytes.NewReader(corrupted)); err == nil {
+		t.Errorf("A corrupted snapshot should be an error")
+	} else if _, ok := err.(*graph.SnapshotChecksumError); !ok {
+		t.Errorf("Error should be a SnapshotChecksumError: %v", err)
+	}
+
+	newer := append([]byte(nil), data...)
+	newer[4] = graph.SnapshotVersion + 1
+	if _, err := graph.ReadSnapshot(bytes.NewReader(newer)); err == nil {
+		t.Errorf("A newer version should be an error")
+	} else if versionErr, ok := err.(*graph.SnapshotVersionError); !ok || versionErr.Version != graph.SnapshotVersion+1 {
+		t.Errorf("Error should be a SnapshotVersionError: %v", err)
+	}
+
+	// a destination out of range with a valid checksum
+	broken := append([]byte(nil), data...)
+	end := len(broken) - 4
+	i := bytes.Index(broken, []byte("e\x05\x09\x03")) // the last key, the counts and the degree of the vertex a
+	if i < 0 {
+		t.Errorf("The arcs of the vertex a should follow the string table")
+		return
+	}
+	broken[i+4] = 40 // the destination of the first arc
+	binary.LittleEndian.PutUint32(broken[end:], crc32.Checksum(broken[:end], crc32.MakeTable(crc32.Castagnoli)))
+	if _, err := graph.ReadSnapshot(bytes.NewReader(broken)); err == nil {
+		t.Errorf("A broken snapshot should be an error")
+	} else if snapshotErr, ok := err.(*graph.SnapshotError); !ok || snapshotErr.Truncated || snapshotErr.Message != "index 40 out of range" {
+		t.Errorf("Error should be a SnapshotError: %v", err)
+	}
+
+	if _, err := graph.ReadSnapshot(bytes.NewReader([]byte("JSON{}"))); err == nil {
+		t.Errorf("Other data should be an error")
+	}
+}
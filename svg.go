@@ -0,0 +1,197 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Layout is a method to place the vertices of a drawing.
+type Layout int
+
+const (
+	ForceDirectedLayout Layout = iota // Fruchterman-Reingold, see LayoutForceDirected
+	LayeredLayout                     // Sugiyama, see LayoutLayered
+)
+
+// SVGOptions controls how a graph is drawn by WriteSVG.
+type SVGOptions struct {
+	Layout        Layout
+	Width         float64 // size of the picture; 800 x 600 when 0
+	Height        float64
+	Iterations    int      // steps of the force-directed layout; 300 when 0
+	Seed          int64    // seed of the force-directed layout
+	Route         []string // route to highlight, e.g. the result of FindShortestRoute
+	HighlightTree bool     // highlight the arcs whose InTree flag is set, e.g. after FindMinArborescence
+	Color         string   // color of the highlights; "red" when empty
+}
+
+// Radius of the circle of a vertex and margin around the drawing.
+const (
+	svgRadius = 18.0
+	svgMargin = 40.0
+)
+
+// Escape text for SVG.
+func escapeSVG(text string) string {
+	var out strings.Builder
+	xml.EscapeText(&out, []byte(text))
+	return out.String()
+}
+
+// Format a coordinate.
+func svgNumber(x float64) string {
+	return strconv.FormatFloat(math.Round(x*10)/10, 'f', -1, 64)
+}
+
+// Return the point at 'distance' from 'from' towards 'to'.
+func towards(from, to Position, distance float64) Position {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return from
+	}
+	return Position{X: from.X + dx/length*distance, Y: from.Y + dy/length*distance}
+}
+
+// Lay the graph out and write it as an SVG picture. Vertices are circles labelled with their keys and arcs are
+// arrows labelled with their weights; arcs between the same two vertices are curved apart. The vertices and arcs
+// of options.Route and, when options.HighlightTree is set, the arcs in the tree are drawn thicker with options.Color.
+func (graph *Graph) WriteSVG(w io.Writer, options SVGOptions) error {
+	width, height := options.Width, options.Height
+	if width == 0 {
+		width = 800
+	}
+	if height == 0 {
+		height = 600
+	}
+	if width <= 2*svgMargin || height <= 2*svgMargin {
+		return errors.New("Picture is too small")
+	}
+	iterations := options.Iterations
+	if iterations == 0 {
+		iterations = 300
+	}
+	color, err := diagramColor(options.Color)
+	if err != nil {
+		return err
+	}
+	var d drawing
+	switch options.Layout {
+	case ForceDirectedLayout:
+		d = graph.forceDirected(width-2*svgMargin, height-2*svgMargin, iterations, options.Seed)
+	case LayeredLayout:
+		d = graph.layered(width-2*svgMargin, height-2*svgMargin)
+	default:
+		return errors.New("Unknown layout")
+	}
+	at := func(vPtr *Vertex) Position {
+		p := d.positions[vPtr]
+		return Position{X: p.X + svgMargin, Y: p.Y + svgMargin}
+	}
+	highlighted, onRoute := graph.routeArcs(options.Route)
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s\" height=\"%s\" viewBox=\"0 0 %s %s\" font-family=\"sans-serif\" font-size=\"12\">\n",
+		svgNumber(width), svgNumber(height), svgNumber(width), svgNumber(height))
+	fmt.Fprint(out, "<defs>\n")
+	for _, marker := range []struct{ id, fill string }{{"arrow", "#555"}, {"arrow-highlight", color}} {
+		fmt.Fprintf(out, "<marker id=\"%s\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"7\" markerHeight=\"7\" orient=\"auto\">"+
+			"<path d=\"M 0 0 L 10 5 L 0 10 z\" fill=\"%s\"/></marker>\n", marker.id, marker.fill)
+	}
+	fmt.Fprint(out, "</defs>\n<g class=\"arcs\">\n")
+
+	// arcs between the same two vertices, in either direction, are spread apart
+	type pair struct{ a, b *Vertex }
+	siblings := make(map[pair][]*Arc)
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			p := pair{vPtr, aPtr.Dest}
+			if aPtr.Dest.Key < vPtr.Key {
+				p = pair{aPtr.Dest, vPtr}
+			}
+			siblings[p] = append(siblings[p], aPtr)
+		}
+	}
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			stroke, strokeWidth, marker := "#555", "1.5", "arrow"
+			if highlighted[aPtr] || (options.HighlightTree && aPtr.InTree) {
+				stroke, strokeWidth, marker = color, "3", "arrow-highlight"
+			}
+			from, to := at(vPtr), at(aPtr.Dest)
+			var path string
+			var label Position
+			switch {
+			case vPtr == aPtr.Dest: // a loop above the vertex
+				path = fmt.Sprintf("M %s %s C %s %s %s %s %s %s", svgNumber(from.X-svgRadius*0.6), svgNumber(from.Y-svgRadius*0.8),
+					svgNumber(from.X-svgRadius*1.5), svgNumber(from.Y-svgRadius*3), svgNumber(from.X+svgRadius*1.5), svgNumber(from.Y-svgRadius*3),
+					svgNumber(from.X+svgRadius*0.6), svgNumber(from.Y-svgRadius*0.8))
+				label = Position{X: from.X, Y: from.Y - svgRadius*2.5}
+			case d.bends[aPtr] != nil: // a polyline through the bends
+				points := append(append([]Position{from}, d.bends[aPtr]...), to)
+				points[0] = towards(from, points[1], svgRadius)
+				points[len(points)-1] = towards(to, points[len(points)-2], svgRadius)
+				var parts []string
+				for i, p := range points {
+					command := "L"
+					if i == 0 {
+						command = "M"
+					}
+					parts = append(parts, command+" "+svgNumber(p.X)+" "+svgNumber(p.Y))
+				}
+				path = strings.Join(parts, " ")
+				label = points[len(points)/2]
+			default: // a straight line, or a curve bowed away from the arcs between the same vertices
+				p := pair{vPtr, aPtr.Dest}
+				sign := 1.0
+				if aPtr.Dest.Key < vPtr.Key {
+					p, sign = pair{aPtr.Dest, vPtr}, -1
+				}
+				group := siblings[p]
+				k := 0
+				for group[k] != aPtr {
+					k++
+				}
+				offset := (float64(k) - float64(len(group)-1)/2) * 24 * sign
+				if len(group) == 2 && offset == 0 {
+					offset = 12 * sign
+				}
+				dx, dy := to.X-from.X, to.Y-from.Y
+				length := math.Max(math.Hypot(dx, dy), 1)
+				normal := Position{X: -dy / length, Y: dx / length}
+				control := Position{X: (from.X+to.X)/2 + normal.X*offset*2, Y: (from.Y+to.Y)/2 + normal.Y*offset*2}
+				start, end := towards(from, control, svgRadius), towards(to, control, svgRadius)
+				if offset == 0 {
+					path = fmt.Sprintf("M %s %s L %s %s", svgNumber(start.X), svgNumber(start.Y), svgNumber(end.X), svgNumber(end.Y))
+				} else {
+					path = fmt.Sprintf("M %s %s Q %s %s %s %s", svgNumber(start.X), svgNumber(start.Y),
+						svgNumber(control.X), svgNumber(control.Y), svgNumber(end.X), svgNumber(end.Y))
+				}
+				label = Position{X: (from.X+to.X)/2 + normal.X*offset, Y: (from.Y+to.Y)/2 + normal.Y*offset}
+			}
+			fmt.Fprintf(out, "<path d=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"%s\" marker-end=\"url(#%s)\"/>\n", path, stroke, strokeWidth, marker)
+			fmt.Fprintf(out, "<text x=\"%s\" y=\"%s\" text-anchor=\"middle\" fill=\"#333\" stroke=\"white\" stroke-width=\"3\" paint-order=\"stroke\">%s</text>\n",
+				svgNumber(label.X), svgNumber(label.Y-3), escapeSVG(strconv.FormatFloat(aPtr.Weight, 'g', -1, 64)))
+		}
+	}
+	fmt.Fprint(out, "</g>\n<g class=\"vertices\">\n")
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		p := at(vPtr)
+		stroke, strokeWidth := "#333", "1.5"
+		if onRoute[vPtr.Key] {
+			stroke, strokeWidth = color, "3"
+		}
+		fmt.Fprintf(out, "<circle cx=\"%s\" cy=\"%s\" r=\"%s\" fill=\"white\" stroke=\"%s\" stroke-width=\"%s\"/>\n",
+			svgNumber(p.X), svgNumber(p.Y), svgNumber(svgRadius), stroke, strokeWidth)
+		fmt.Fprintf(out, "<text x=\"%s\" y=\"%s\" text-anchor=\"middle\" dominant-baseline=\"central\">%s</text>\n",
+			svgNumber(p.X), svgNumber(p.Y), escapeSVG(vPtr.Key))
+	}
+	fmt.Fprint(out, "</g>\n</svg>\n")
+	return out.Flush()
+}
@@ -0,0 +1,107 @@
+package graph_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/audathuynh/graph"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Check that the document is well-formed XML.
+func checkXML(t *testing.T, document string) {
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Errorf("The SVG should be well-formed: %v\n%s", err, document)
+			return
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	fmt.Println("Testing SVG rendering")
+	g := graph.NewGraph()
+	initGraph(g)
+	route, _ := g.FindShortestRoute("a", "c")
+	for _, layout := range []graph.Layout{graph.ForceDirectedLayout, graph.LayeredLayout} {
+		var out strings.Builder
+		if err := g.WriteSVG(&out, graph.SVGOptions{Layout: layout, Route: route, Color: "#00aa00"}); err != nil {
+			t.Errorf("Error should be NIL: %v", err)
+			continue
+		}
+		svg := out.String()
+		checkXML(t, svg)
+		if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="800" height="600"`) {
+			t.Errorf("The SVG should start with its size:\n%s", svg)
+		}
+		if strings.Count(svg, "<circle ") != 5 || strings.Count(svg, `fill="none"`) != 9 {
+			t.Errorf("The SVG should have 5 vertices and 9 arcs:\n%s", svg)
+		}
+		// the route a-b-c has 2 highlighted arcs and 3 highlighted vertices
+		if strings.Count(svg, `marker-end="url(#arrow-highlight)"`) != 2 || strings.Count(svg, `stroke="#00aa00" stroke-width="3"/>`) != 3 {
+			t.Errorf("The route should be highlighted:\n%s", svg)
+		}
+		if !strings.Contains(svg, ">5</text>") || !strings.Contains(svg, ">e</text>") {
+			t.Errorf("The SVG should label weights and keys:\n%s", svg)
+		}
+	}
+
+	// c-d and d-c are curved apart rather than drawn over each other
+	var out strings.Builder
+	g.WriteSVG(&out, graph.SVGOptions{})
+	if strings.Count(out.String(), " Q ") != 2 {
+		t.Errorf("Opposite arcs should be curved:\n%s", out.String())
+	}
+	var again strings.Builder
+	g.WriteSVG(&again, graph.SVGOptions{})
+	if out.String() != again.String() {
+		t.Errorf("The same options should give the same picture")
+	}
+}
+
+func TestWriteSVGTree(t *testing.T) {
+	fmt.Println("Testing SVG rendering of an arborescence")
+	g := graph.NewGraph()
+	initGraph(g)
+	if _, _, err := g.FindMinArborescence("a"); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	var out strings.Builder
+	g.WriteSVG(&out, graph.SVGOptions{Layout: graph.LayeredLayout, HighlightTree: true})
+	// the arborescence spans the 5 vertices with 4 arcs
+	if strings.Count(out.String(), `marker-end="url(#arrow-highlight)"`) != 4 {
+		t.Errorf("The tree should be highlighted:\n%s", out.String())
+	}
+}
+
+func TestWriteSVGEscaping(t *testing.T) {
+	fmt.Println("Testing SVG escaping and errors")
+	g := graph.NewGraph()
+	g.InsertVertex(`<a & "b">`)
+	g.InsertArc(`<a & "b">`, `<a & "b">`, 1)
+	var out strings.Builder
+	if err := g.WriteSVG(&out, graph.SVGOptions{}); err != nil {
+		t.Errorf("Error should be NIL: %v", err)
+		return
+	}
+	checkXML(t, out.String())
+	if !strings.Contains(out.String(), ">&lt;a &amp; &#34;b&#34;&gt;</text>") || !strings.Contains(out.String(), " C ") {
+		t.Errorf("The key should be escaped and the loop drawn:\n%s", out.String())
+	}
+	if err := g.WriteSVG(&out, graph.SVGOptions{Color: "red;x"}); err == nil {
+		t.Errorf("An invalid color should be an error")
+	}
+	if err := g.WriteSVG(&out, graph.SVGOptions{Width: 50}); err == nil {
+		t.Errorf("A picture too small should be an error")
+	}
+	if err := g.WriteSVG(&out, graph.SVGOptions{Layout: 9}); err == nil {
+		t.Errorf("An unknown layout should be an error")
+	}
+}
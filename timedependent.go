@@ -0,0 +1,176 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// TravelTime gives the travel time along an arc as a function of the departure time. Implementations must have
+// the FIFO property: leaving later never means arriving earlier.
+type TravelTime interface {
+	Duration(departure float64) float64 // travel time when leaving at 'departure'; INFINITY when the arc cannot be used
+	MinDuration() float64               // lower bound of the travel time, used as the Weight of the arc
+}
+
+// PiecewiseLinearTime is a travel time interpolated linearly between breakpoints.
+// Before the first and after the last breakpoint the travel time is constant.
+type PiecewiseLinearTime struct {
+	times     []float64
+	durations []float64
+}
+
+// Create a piecewise linear travel time with the travel time durations[i] when leaving at times[i].
+// Times must be increasing, and the travel time must never decrease faster than time passes (FIFO property).
+func NewPiecewiseLinearTime(times, durations []float64) (*PiecewiseLinearTime, error) {
+	if len(times) == 0 || len(times) != len(durations) {
+		return nil, errors.New("Times and durations must have the same non-zero length")
+	}
+	for i := range times {
+		if durations[i] < 0 {
+			return nil, errors.New("Durations must not be negative")
+		}
+		if i == 0 {
+			continue
+		}
+		if times[i] <= times[i-1] {
+			return nil, errors.New("Times must be increasing")
+		}
+		if times[i]+durations[i] < times[i-1]+durations[i-1] {
+			return nil, errors.New("Travel times violate the FIFO property")
+		}
+	}
+	return &PiecewiseLinearTime{times: append([]float64(nil), times...), durations: append([]float64(nil), durations...)}, nil
+}
+
+func (p *PiecewiseLinearTime) Duration(departure float64) float64 {
+	n := len(p.times)
+	if departure <= p.times[0] {
+		return p.durations[0]
+	}
+	if departure >= p.times[n-1] {
+		return p.durations[n-1]
+	}
+	i := sort.SearchFloat64s(p.times, departure) // times[i-1] < departure <= times[i]
+	ratio := (departure - p.times[i-1]) / (p.times[i] - p.times[i-1])
+	return p.durations[i-1] + ratio*(p.durations[i]-p.durations[i-1])
+}
+
+func (p *PiecewiseLinearTime) MinDuration() float64 {
+	min := p.durations[0]
+	for _, d := range p.durations {
+		min = math.Min(min, d)
+	}
+	return min
+}
+
+// ScheduledTime is the travel time of an arc served by scheduled departures. The travel time includes the wait
+// for the next departure; the earliest arrival among the later departures is used, so overtaking is respected.
+type ScheduledTime struct {
+	departures []float64
+	arrivals   []float64 // arrivals[i] is the earliest arrival when leaving at departures[i] or later
+	min        float64
+}
+
+// Create a scheduled travel time with departures at departures[i] taking durations[i].
+func NewScheduledTime(departures, durations []float64) (*ScheduledTime, error) {
+	if len(departures) == 0 || len(departures) != len(durations) {
+		return nil, errors.New("Departures and durations must have the same non-zero length")
+	}
+	order := make([]int, len(departures))
+	for i := range order {
+		if durations[i] < 0 {
+			return nil, errors.New("Durations must not be negative")
+		}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return departures[order[a]] < departures[order[b]] })
+	s := &ScheduledTime{departures: make([]float64, len(order)), arrivals: make([]float64, len(order)), min: math.Inf(1)}
+	for k, i := range order {
+		s.departures[k] = departures[i]
+		s.arrivals[k] = departures[i] + durations[i]
+		s.min = math.Min(s.min, durations[i])
+	}
+	for k := len(order) - 2; k >= 0; k-- {
+		s.arrivals[k] = math.Min(s.arrivals[k], s.arrivals[k+1])
+	}
+	return s, nil
+}
+
+func (s *ScheduledTime) Duration(departure float64) float64 {
+	k := sort.SearchFloat64s(s.departures, departure)
+	if k == len(s.departures) {
+		return math.Inf(1)
+	}
+	return s.arrivals[k] - departure
+}
+
+func (s *ScheduledTime) MinDuration() float64 {
+	return s.min
+}
+
+// Insert an arc whose travel time depends on the departure time. The weight of the arc is the lower bound
+// of the travel time.
+func (graph *Graph) InsertTimedArc(fromKey, toKey string, travelTime TravelTime) error {
+	aPtr, err := graph.insertArc(fromKey, toKey, travelTime.MinDuration())
+	if err != nil {
+		return err
+	}
+	aPtr.TravelTime = travelTime
+	return nil
+}
+
+// Return the travel time along an arc when leaving at 'departure'.
+func arcDuration(aPtr *Arc, departure float64) float64 {
+	if aPtr.TravelTime != nil {
+		return aPtr.TravelTime.Duration(departure)
+	}
+	return aPtr.Weight
+}
+
+// TimedStop is a stop of a route with the time of arrival.
+type TimedStop struct {
+	Key     string
+	Arrival float64
+}
+
+// arrivalTime is the ShortestPath semiring starting from the time of departure: the value of a path is the time
+// of arrival at its end.
+type arrivalTime struct {
+	departure float64
+}
+
+func (arrivalTime) Combine(a, b float64) float64 { return math.Min(a, b) }
+func (arrivalTime) Extend(a, b float64) float64  { return a + b }
+func (arrivalTime) Zero() float64                { return math.MaxFloat64 }
+func (t arrivalTime) One() float64               { return t.departure }
+
+// Find the route from the vertex 'fromKey' to the vertex 'toKey' arriving as early as possible when leaving at
+// 'departure', with a time-dependent uniform-cost search. Arcs without a TravelTime take their Weight.
+// The result lists the arrival time at every stop.
+func (graph *Graph) FindEarliestArrival(fromKey, toKey string, departure float64) ([]TimedStop, error) {
+	if graph.First == nil {
+		return nil, errors.New("Graph is empty")
+	}
+	vFromPtr := graph.findVertex(fromKey)
+	if vFromPtr == nil {
+		return nil, errors.New("FromKey not found")
+	}
+	vToPtr := graph.findVertex(toKey)
+	if vToPtr == nil {
+		return nil, errors.New("ToKey not found")
+	}
+	// the PathLength of a vertex is the earliest arrival time found so far
+	vPtr := graph.search(vFromPtr, vToPtr, arrivalTime{departure}, func(from *Vertex, arc *Arc) (float64, bool) {
+		duration := arcDuration(arc, from.PathLength)
+		return duration, !math.IsInf(duration, 1)
+	})
+	if vPtr != nil {
+		var result []TimedStop
+		for ptr := vPtr; ptr != nil; ptr = ptr.Parent {
+			result = append([]TimedStop{{Key: ptr.Key, Arrival: ptr.PathLength}}, result...)
+		}
+		return result, nil
+	}
+	return nil, errors.New("No solution found")
+}
@@ -0,0 +1,53 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTravelTimes(t *testing.T) {
+	fmt.Println("Testing travel time functions")
+	linear, err := graph.NewPiecewiseLinearTime([]float64{0, 60, 120}, []float64{10, 30, 10})
+	if err != nil {
+		t.Errorf("Error should be NIL")
+		return
+	}
+	if linear.Duration(30) != 20 || linear.Duration(200) != 10 || linear.MinDuration() != 10 {
+		t.Errorf("Piecewise linear travel time is not correct")
+	}
+	if _, err := graph.NewPiecewiseLinearTime([]float64{0, 10}, []float64{30, 5}); err == nil {
+		t.Errorf("Travel times violating FIFO should be rejected")
+	}
+
+	scheduled, _ := graph.NewScheduledTime([]float64{10, 20, 30}, []float64{15, 2, 5})
+	// leaving at 5 waits for the departure at 20, which overtakes the one at 10
+	if scheduled.Duration(5) != 17 || scheduled.Duration(25) != 10 || !math.IsInf(scheduled.Duration(31), 1) {
+		t.Errorf("Scheduled travel time is not correct")
+	}
+}
+
+func TestEarliestArrival(t *testing.T) {
+	fmt.Println("Testing earliest arrival")
+	g := graph.NewGraph()
+	initGraph(g)
+	rush, err := graph.NewPiecewiseLinearTime([]float64{0, 10, 20}, []float64{4, 20, 12})
+	if err != nil {
+		t.Errorf("Error should be NIL")
+		return
+	}
+	g.InsertTimedArc("a", "c", rush)
+
+	result, err := g.FindEarliestArrival("a", "c", 0)
+	if err != nil || !reflect.DeepEqual(result, []graph.TimedStop{{Key: "a", Arrival: 0}, {Key: "c", Arrival: 4}}) {
+		t.Errorf("Solution is not correct")
+	}
+	result, err = g.FindEarliestArrival("a", "c", 10)
+	fmt.Println(result)
+	expected := []graph.TimedStop{{Key: "a", Arrival: 10}, {Key: "b", Arrival: 15}, {Key: "c", Arrival: 19}}
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Errorf("Solution is not correct")
+	}
+}
@@ -0,0 +1,266 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// StopTime is the arrival and departure of a trip at a station. Stations use the same keys as Graph vertices.
+type StopTime struct {
+	StopKey   string
+	Arrival   float64
+	Departure float64
+}
+
+// Trip is one run of a vehicle along its stops.
+type Trip struct {
+	ID        string
+	RouteID   string
+	StopTimes []StopTime
+}
+
+// Transfer is a walk between two stations.
+type Transfer struct {
+	FromKey  string
+	ToKey    string
+	Duration float64
+}
+
+// Leg is a part of a journey: a ride on a trip, or a transfer when TripID is empty.
+type Leg struct {
+	TripID    string
+	FromKey   string
+	ToKey     string
+	Departure float64
+	Arrival   float64
+}
+
+// Journey is a sequence of legs from one station to another.
+type Journey struct {
+	Legs      []Leg
+	Departure float64
+	Arrival   float64
+}
+
+// An elementary connection: a trip going from one station to the next without stopping.
+type connection struct {
+	trip      int
+	from, to  string
+	departure float64
+	arrival   float64
+}
+
+// Timetable holds scheduled trips and transfers. Journeys are found with the Connection Scan Algorithm.
+// Changing trips at the same station takes no time; walks between stations are given as transfers.
+type Timetable struct {
+	Trips     []*Trip
+	Transfers map[string][]Transfer // transfers leaving every station
+
+	connections []connection // sorted by departure time; built when a query needs them
+}
+
+func NewTimetable() *Timetable {
+	return &Timetable{Transfers: make(map[string][]Transfer)}
+}
+
+// Add a trip with the stop times 'stopTimes' in the order of the stops.
+func (timetable *Timetable) AddTrip(id, routeID string, stopTimes []StopTime) error {
+	if len(stopTimes) < 2 {
+		return errors.New("Trip " + id + " must have at least two stops")
+	}
+	for i, st := range stopTimes {
+		if st.Departure < st.Arrival || (i > 0 && st.Arrival < stopTimes[i-1].Departure) {
+			return errors.New("Stop times of trip " + id + " are not in order")
+		}
+	}
+	timetable.Trips = append(timetable.Trips, &Trip{ID: id, RouteID: routeID, StopTimes: append([]StopTime(nil), stopTimes...)})
+	timetable.connections = nil
+	return nil
+}
+
+// Add a walk from the station 'fromKey' to the station 'toKey' taking 'duration'.
+func (timetable *Timetable) AddTransfer(fromKey, toKey string, duration float64) error {
+	if duration < 0 {
+		return errors.New("Transfer duration must not be negative")
+	}
+	timetable.Transfers[fromKey] = append(timetable.Transfers[fromKey], Transfer{FromKey: fromKey, ToKey: toKey, Duration: duration})
+	return nil
+}
+
+// Return the elementary connections of all trips sorted by departure time.
+func (timetable *Timetable) sortedConnections() []connection {
+	if timetable.connections == nil {
+		for i, trip := range timetable.Trips {
+			for k := 0; k+1 < len(trip.StopTimes); k++ {
+				timetable.connections = append(timetable.connections, connection{trip: i,
+					from: trip.StopTimes[k].StopKey, to: trip.StopTimes[k+1].StopKey,
+					departure: trip.StopTimes[k].Departure, arrival: trip.StopTimes[k+1].Arrival})
+			}
+		}
+		sort.SliceStable(timetable.connections, func(a, b int) bool {
+			return timetable.connections[a].departure < timetable.connections[b].departure
+		})
+	}
+	return timetable.connections
+}
+
+// How a station was reached in the connection scan: by a trip boarded at connection 'enter' and left at
+// connection 'exit', or by a transfer when 'transfer' is not nil.
+type reachedBy struct {
+	enter, exit int
+	transfer    *Transfer
+}
+
+// Find the journey from the station 'fromKey' to the station 'toKey' arriving as early as possible when
+// leaving at 'departure', with the Connection Scan Algorithm.
+func (timetable *Timetable) EarliestArrival(fromKey, toKey string, departure float64) (*Journey, error) {
+	connections := timetable.sortedConnections()
+	arrival := make(map[string]float64)
+	reached := make(map[string]reachedBy)
+	get := func(key string) float64 {
+		if t, ok := arrival[key]; ok {
+			return t
+		}
+		return math.Inf(1)
+	}
+	walk := func(key string) {
+		for i := range timetable.Transfers[key] {
+			transfer := &timetable.Transfers[key][i]
+			if t := arrival[key] + transfer.Duration; t < get(transfer.ToKey) {
+				arrival[transfer.ToKey] = t
+				reached[transfer.ToKey] = reachedBy{transfer: transfer}
+			}
+		}
+	}
+	arrival[fromKey] = departure
+	walk(fromKey)
+
+	boarded := make(map[int]int) // connection where every trip was boarded
+	start := sort.Search(len(connections), func(i int) bool { return connections[i].departure >= departure })
+	for i := start; i < len(connections); i++ {
+		c := connections[i]
+		if c.departure >= get(toKey) {
+			break
+		}
+		enter, onBoard := boarded[c.trip]
+		if !onBoard {
+			if get(c.from) > c.departure {
+				continue
+			}
+			enter = i
+			boarded[c.trip] = i
+		}
+		if c.arrival < get(c.to) {
+			arrival[c.to] = c.arrival
+			reached[c.to] = reachedBy{enter: enter, exit: i}
+			walk(c.to)
+		}
+	}
+	if math.IsInf(get(toKey), 1) {
+		return nil, errors.New("No journey found")
+	}
+
+	// trace the journey back from the destination
+	journey := &Journey{Arrival: arrival[toKey]}
+	for key := toKey; key != fromKey; {
+		by := reached[key]
+		var leg Leg
+		if by.transfer != nil {
+			leg = Leg{FromKey: by.transfer.FromKey, ToKey: key, Departure: arrival[key] - by.transfer.Duration, Arrival: arrival[key]}
+		} else {
+			enter, exit := connections[by.enter], connections[by.exit]
+			leg = Leg{TripID: timetable.Trips[enter.trip].ID, FromKey: enter.from, ToKey: key, Departure: enter.departure, Arrival: exit.arrival}
+		}
+		journey.Legs = append([]Leg{leg}, journey.Legs...)
+		key = leg.FromKey
+	}
+	journey.Departure = departure
+	if len(journey.Legs) > 0 {
+		journey.Departure = journey.Legs[0].Departure
+	}
+	return journey, nil
+}
+
+// Find every journey from the station 'fromKey' to the station 'toKey' leaving between 'earliest' and 'latest'
+// that is not beaten by a journey leaving later and arriving no later. An earliest-arrival scan is run for every
+// departure time from the station, from the latest to the earliest. Journeys are returned by departure time.
+func (timetable *Timetable) Profile(fromKey, toKey string, earliest, latest float64) ([]Journey, error) {
+	if earliest > latest {
+		return nil, errors.New("Earliest departure is after the latest departure")
+	}
+	// departures from the station, directly or after a transfer
+	var times []float64
+	for _, c := range timetable.sortedConnections() {
+		if c.from == fromKey {
+			times = append(times, c.departure)
+		}
+		for _, transfer := range timetable.Transfers[fromKey] {
+			if c.from == transfer.ToKey {
+				times = append(times, c.departure-transfer.Duration)
+			}
+		}
+	}
+	sort.Float64s(times)
+	var profile []Journey
+	best := math.Inf(1)
+	for i := len(times) - 1; i >= 0; i-- {
+		t := times[i]
+		if t < earliest || t > latest || (i+1 < len(times) && times[i+1] == t) {
+			continue
+		}
+		journey, err := timetable.EarliestArrival(fromKey, toKey, t)
+		if err != nil || journey.Arrival >= best || journey.Departure > latest {
+			continue
+		}
+		best = journey.Arrival
+		profile = append([]Journey{*journey}, profile...)
+	}
+	return profile, nil
+}
+
+// Build a graph with the stations of the trips as vertices and an arc between every two consecutive stops,
+// weighted by the shortest ride between them.
+func (timetable *Timetable) ToGraph() *Graph {
+	return timetable.buildGraph(nil)
+}
+
+// Build the graph of ToGraph with the stations 'stations' as additional vertices, even when no trip stops there.
+func (timetable *Timetable) buildGraph(stations []string) *Graph {
+	stops := make(map[string]bool)
+	for _, key := range stations {
+		stops[key] = true
+	}
+	rides := make(map[Edge]float64)
+	var pairs []Edge
+	for _, trip := range timetable.Trips {
+		for k, st := range trip.StopTimes {
+			stops[st.StopKey] = true
+			if k == 0 || trip.StopTimes[k-1].StopKey == st.StopKey {
+				continue
+			}
+			pair := Edge{FromKey: trip.StopTimes[k-1].StopKey, ToKey: st.StopKey}
+			ride := st.Arrival - trip.StopTimes[k-1].Departure
+			if old, ok := rides[pair]; !ok {
+				pairs = append(pairs, pair)
+				rides[pair] = ride
+			} else if ride < old {
+				rides[pair] = ride
+			}
+		}
+	}
+	// the stations are inserted in order so that the builder appends every vertex
+	keys := make([]string, 0, len(stops))
+	for key := range stops {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	builder := newGraphBuilder()
+	for _, key := range keys {
+		builder.vertex(key)
+	}
+	for _, pair := range pairs {
+		linkArc(builder.vertices[pair.FromKey], builder.vertices[pair.ToKey], rides[pair])
+	}
+	return builder.graph
+}
@@ -0,0 +1,65 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"testing"
+)
+
+func initTimetable(timetable *graph.Timetable) {
+	timetable.AddTrip("T1", "R1", []graph.StopTime{
+		{StopKey: "a", Arrival: 10, Departure: 10},
+		{StopKey: "b", Arrival: 20, Departure: 21},
+		{StopKey: "c", Arrival: 30, Departure: 30}})
+	timetable.AddTrip("T2", "R2", []graph.StopTime{
+		{StopKey: "b", Arrival: 22, Departure: 22},
+		{StopKey: "d", Arrival: 40, Departure: 40}})
+	timetable.AddTrip("T3", "R3", []graph.StopTime{
+		{StopKey: "a", Arrival: 15, Departure: 15},
+		{StopKey: "d", Arrival: 35, Departure: 35}})
+	timetable.AddTransfer("c", "d", 3)
+}
+
+func TestTimetableEarliestArrival(t *testing.T) {
+	fmt.Println("Testing timetable earliest arrival")
+	timetable := graph.NewTimetable()
+	initTimetable(timetable)
+
+	journey, err := timetable.EarliestArrival("a", "d", 0)
+	if err != nil {
+		t.Errorf("Error should be NIL")
+		return
+	}
+	fmt.Println(journey)
+	expected := []graph.Leg{
+		{TripID: "T1", FromKey: "a", ToKey: "c", Departure: 10, Arrival: 30},
+		{FromKey: "c", ToKey: "d", Departure: 30, Arrival: 33}}
+	if !reflect.DeepEqual(journey.Legs, expected) || journey.Departure != 10 || journey.Arrival != 33 {
+		t.Errorf("Solution is not correct")
+	}
+
+	journey, err = timetable.EarliestArrival("a", "d", 12)
+	if err != nil || journey.Arrival != 35 || journey.Legs[0].TripID != "T3" {
+		t.Errorf("Solution is not correct")
+	}
+	_, err = timetable.EarliestArrival("a", "d", 16)
+	if err == nil {
+		t.Errorf("Error should NOT be NIL")
+	}
+}
+
+func TestTimetableProfile(t *testing.T) {
+	fmt.Println("Testing timetable profile")
+	timetable := graph.NewTimetable()
+	initTimetable(timetable)
+
+	profile, err := timetable.Profile("a", "d", 0, 20)
+	if err != nil || len(profile) != 2 {
+		t.Errorf("There should be two journeys")
+		return
+	}
+	if profile[0].Departure != 10 || profile[0].Arrival != 33 || profile[1].Departure != 15 || profile[1].Arrival != 35 {
+		t.Errorf("Solution is not correct")
+	}
+}
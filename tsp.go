@@ -0,0 +1,250 @@
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+// Held-Karp keeps a table of 2^n entries per station, so it is only used for small sets of stations.
+const maxExactTourStations = 16
+
+// Tour is a closed walk visiting a set of stations.
+type Tour struct {
+	Stations []string // stations in the order of the visits, starting and ending at the first station
+	Route    []string // the tour expanded into the vertices of the graph
+	Weight   float64
+}
+
+// The metric closure of a set of stations: shortest distances and routes between every pair of them.
+type metricClosure struct {
+	keys   []string
+	dist   [][]float64
+	routes [][][]string
+}
+
+// Compute the shortest distances and routes between every pair of the stations 'keys', using only the arcs
+// accepted by 'allow'. Unreachable pairs get an INFINITE distance and a nil route.
+func (graph *Graph) closure(keys []string, allow func(from *Vertex, arc *Arc) bool) (*metricClosure, error) {
+	if graph.First == nil {
+		return nil, errors.New("Graph is empty")
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("No stations given")
+	}
+	stations := make([]*Vertex, len(keys))
+	for i, key := range keys {
+		stations[i] = graph.findVertex(key)
+		if stations[i] == nil {
+			return nil, errors.New("Vertex " + key + " not found")
+		}
+	}
+	m := &metricClosure{keys: keys, dist: make([][]float64, len(keys)), routes: make([][][]string, len(keys))}
+	for i, from := range stations {
+		graph.findShortestDistances(from, allow)
+		m.dist[i] = make([]float64, len(keys))
+		m.routes[i] = make([][]string, len(keys))
+		for j, to := range stations {
+			if from == to {
+				m.routes[i][j] = []string{from.Key}
+			} else if to.PathLength == math.MaxFloat64 {
+				m.dist[i][j] = math.Inf(1)
+			} else {
+				m.dist[i][j] = to.PathLength
+				m.routes[i][j] = processSolution(to)
+			}
+		}
+	}
+	return m, nil
+}
+
+// Compute the metric closure of the stations of a tour. Every station must be given once and be able to reach
+// every other station.
+func (graph *Graph) tourClosure(keys []string) (*metricClosure, error) {
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if seen[key] {
+			return nil, errors.New("Station " + key + " is given twice")
+		}
+		seen[key] = true
+	}
+	m, err := graph.closure(keys, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		for j := range keys {
+			if math.IsInf(m.dist[i][j], 1) {
+				return nil, errors.New("Station " + keys[j] + " cannot be reached from station " + keys[i])
+			}
+		}
+	}
+	return m, nil
+}
+
+// Return the weight of the closed tour visiting the stations in the order 'order'.
+func (m *metricClosure) tourWeight(order []int) float64 {
+	weight := 0.0
+	for i := range order {
+		weight += m.dist[order[i]][order[(i+1)%len(order)]]
+	}
+	return weight
+}
+
+// Build the Tour for the order 'order' by joining the shortest routes between consecutive stations.
+func (m *metricClosure) tour(order []int) *Tour {
+	t := &Tour{Route: []string{m.keys[order[0]]}, Weight: m.tourWeight(order)}
+	for i := range order {
+		t.Stations = append(t.Stations, m.keys[order[i]])
+		next := order[(i+1)%len(order)]
+		if order[i] != next {
+			t.Route = append(t.Route, m.routes[order[i]][next][1:]...)
+		}
+	}
+	t.Stations = append(t.Stations, m.keys[order[0]])
+	return t
+}
+
+// Find the shortest tour starting from the first station of 'keys' and visiting every station of 'keys'
+// with the Held-Karp dynamic programming algorithm. Distances are the shortest routes in the graph.
+func (graph *Graph) FindExactTour(keys []string) (*Tour, error) {
+	if len(keys) > maxExactTourStations {
+		return nil, errors.New("Too many stations for an exact tour")
+	}
+	m, err := graph.tourClosure(keys)
+	if err != nil {
+		return nil, err
+	}
+	n := len(keys)
+	if n == 1 {
+		return m.tour([]int{0}), nil
+	}
+	// cost[set][j]: cheapest walk from station 0 visiting the stations of 'set' (over stations 1..n-1) and ending at j
+	size := 1 << uint(n-1)
+	cost := make([][]float64, size)
+	prev := make([][]int, size)
+	for set := range cost {
+		cost[set] = make([]float64, n)
+		prev[set] = make([]int, n)
+		for j := range cost[set] {
+			cost[set][j] = math.Inf(1)
+		}
+	}
+	for j := 1; j < n; j++ {
+		cost[1<<uint(j-1)][j] = m.dist[0][j]
+	}
+	for set := 1; set < size; set++ {
+		for j := 1; j < n; j++ {
+			if set&(1<<uint(j-1)) == 0 || math.IsInf(cost[set][j], 1) {
+				continue
+			}
+			for k := 1; k < n; k++ {
+				if set&(1<<uint(k-1)) != 0 {
+					continue
+				}
+				next := set | 1<<uint(k-1)
+				if c := cost[set][j] + m.dist[j][k]; c < cost[next][k] {
+					cost[next][k] = c
+					prev[next][k] = j
+				}
+			}
+		}
+	}
+	full := size - 1
+	last := 1
+	for j := 2; j < n; j++ {
+		if cost[full][j]+m.dist[j][0] < cost[full][last]+m.dist[last][0] {
+			last = j
+		}
+	}
+	order := make([]int, n)
+	set := full
+	for i := n - 1; i > 0; i-- {
+		order[i] = last
+		last, set = prev[set][last], set&^(1<<uint(last-1))
+	}
+	return m.tour(order), nil
+}
+
+// Find a short tour starting from the first station of 'keys' and visiting every station of 'keys'.
+// A nearest neighbour tour is improved with 2-opt and Or-opt moves until no move helps.
+// Distances are the shortest routes in the graph, so arcs need not be symmetric.
+func (graph *Graph) FindHeuristicTour(keys []string) (*Tour, error) {
+	m, err := graph.tourClosure(keys)
+	if err != nil {
+		return nil, err
+	}
+	order := m.nearestNeighbour()
+	improved := true
+	for improved {
+		improved = m.twoOpt(order) || m.orOpt(order)
+	}
+	return m.tour(order), nil
+}
+
+// Build a tour from station 0 by always moving to the closest station not visited yet.
+func (m *metricClosure) nearestNeighbour() []int {
+	n := len(m.keys)
+	visited := make([]bool, n)
+	order := []int{0}
+	visited[0] = true
+	for len(order) < n {
+		current := order[len(order)-1]
+		best := -1
+		for j := 0; j < n; j++ {
+			if !visited[j] && (best == -1 || m.dist[current][j] < m.dist[current][best]) {
+				best = j
+			}
+		}
+		visited[best] = true
+		order = append(order, best)
+	}
+	return order
+}
+
+// Apply the first 2-opt move (reversing a segment) that shortens the tour. Return false when there is none.
+// The first station stays in place.
+func (m *metricClosure) twoOpt(order []int) bool {
+	weight := m.tourWeight(order)
+	for i := 1; i < len(order)-1; i++ {
+		for j := i + 1; j < len(order); j++ {
+			reverse(order[i : j+1])
+			if m.tourWeight(order) < weight-flowEpsilon {
+				return true
+			}
+			reverse(order[i : j+1])
+		}
+	}
+	return false
+}
+
+// Apply the first Or-opt move (moving a segment of up to three stations) that shortens the tour.
+// Return false when there is none. The first station stays in place.
+func (m *metricClosure) orOpt(order []int) bool {
+	weight := m.tourWeight(order)
+	n := len(order)
+	candidate := make([]int, 0, n)
+	for length := 1; length <= 3; length++ {
+		for i := 1; i+length <= n; i++ {
+			segment := order[i : i+length]
+			rest := make([]int, 0, n-length)
+			rest = append(append(rest, order[:i]...), order[i+length:]...)
+			for p := 1; p <= len(rest); p++ {
+				if p == i {
+					continue
+				}
+				candidate = append(append(append(candidate[:0], rest[:p]...), segment...), rest[p:]...)
+				if m.tourWeight(candidate) < weight-flowEpsilon {
+					copy(order, candidate)
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func reverse(values []int) {
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+}
@@ -0,0 +1,59 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"reflect"
+	"testing"
+)
+
+func TestExactTour(t *testing.T) {
+	fmt.Println("Testing exact tour")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	tour, err := g.FindExactTour([]string{"b", "d", "e", "c"})
+	if err != nil {
+		t.Errorf("Error should be NIL")
+		return
+	}
+	fmt.Println(tour.Stations, tour.Route, tour.Weight)
+	if !reflect.DeepEqual(tour.Stations, []string{"b", "c", "d", "e", "b"}) || tour.Weight != 21 {
+		t.Errorf("Solution is not correct")
+	}
+
+	tour, err = g.FindExactTour([]string{"b", "d"})
+	if err != nil {
+		t.Errorf("Error should be NIL")
+		return
+	}
+	// d is reached through c and left through e
+	if !reflect.DeepEqual(tour.Route, []string{"b", "c", "d", "e", "b"}) || tour.Weight != 21 {
+		t.Errorf("The tour should be expanded into arcs of the graph")
+	}
+
+	_, err = g.FindExactTour([]string{"b", "a"})
+	if err == nil {
+		t.Errorf("Error should NOT be NIL")
+	}
+}
+
+func TestHeuristicTour(t *testing.T) {
+	fmt.Println("Testing heuristic tour")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	tour, err := g.FindHeuristicTour([]string{"b", "d", "e", "c"})
+	if err != nil {
+		t.Errorf("Error should be NIL")
+		return
+	}
+	fmt.Println(tour.Stations, tour.Route, tour.Weight)
+	distance, err := g.FindDistance(tour.Route)
+	if err != nil || distance != tour.Weight {
+		t.Errorf("The route should follow arcs of the graph")
+	}
+	if tour.Weight != 21 {
+		t.Errorf("The heuristic should find the best tour")
+	}
+}
@@ -0,0 +1,25 @@
+package graph
+
+import (
+	"errors"
+)
+
+// Find the route from the vertex 'fromKey' to the vertex 'toKey' whose lightest arc is as heavy as possible,
+// e.g. the route with the largest capacity. The result is the route and the weight of its lightest arc.
+func (graph *Graph) FindWidestRoute(fromKey, toKey string) ([]string, float64, error) {
+	return graph.FindBestRoute(fromKey, toKey, WidestPath{})
+}
+
+// Find the route from the vertex 'fromKey' to the vertex 'toKey' whose product of arc weights is the largest,
+// e.g. the most reliable route when weights are probabilities. Every weight must be between 0 and 1.
+// The result is the route and the product of its weights.
+func (graph *Graph) FindMostReliableRoute(fromKey, toKey string) ([]string, float64, error) {
+	for vPtr := graph.First; vPtr != nil; vPtr = vPtr.NextVertex {
+		for aPtr := vPtr.Arc; aPtr != nil; aPtr = aPtr.NextArc {
+			if aPtr.Weight < 0 || aPtr.Weight > 1 {
+				return nil, 0, errors.New("Weights must be probabilities between 0 and 1")
+			}
+		}
+	}
+	return graph.FindBestRoute(fromKey, toKey, ReliablePath{})
+}
@@ -0,0 +1,50 @@
+package graph_test
+
+import (
+	"fmt"
+	"github.com/audathuynh/graph"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestWidestRoute(t *testing.T) {
+	fmt.Println("Testing widest route")
+	g := graph.NewGraph()
+	initGraph(g)
+
+	result, width, err := g.FindWidestRoute("a", "c")
+	fmt.Println(result, width)
+	if err != nil {
+		t.Errorf("Error should be NIL")
+	}
+	if !reflect.DeepEqual(result, []string{"a", "d", "c"}) || width != 5 {
+		t.Errorf("Solution is not correct")
+	}
+}
+
+func TestMostReliableRoute(t *testing.T) {
+	fmt.Println("Testing most reliable route")
+	g := graph.NewGraph()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		g.InsertVertex(key)
+	}
+	g.InsertArc("a", "b", 0.9)
+	g.InsertArc("b", "d", 0.5)
+	g.InsertArc("a", "c", 0.8)
+	g.InsertArc("c", "d", 0.75)
+
+	result, probability, err := g.FindMostReliableRoute("a", "d")
+	fmt.Println(result, probability)
+	if err != nil {
+		t.Errorf("Error should be NIL")
+	}
+	if !reflect.DeepEqual(result, []string{"a", "c", "d"}) || math.Abs(probability-0.6) > 1e-9 {
+		t.Errorf("Solution is not correct")
+	}
+
+	g.InsertArc("a", "d", 2)
+	if _, _, err := g.FindMostReliableRoute("a", "d"); err == nil {
+		t.Errorf("Error should NOT be NIL")
+	}
+}